@@ -0,0 +1,45 @@
+package isplaintextfile
+
+// ansiState tracks progress through an ANSI escape sequence.
+type ansiState uint8
+
+const (
+	ansiNone         ansiState = iota // not in an escape sequence
+	ansiEscape                        // after ESC
+	ansiParams                        // in the parameter bytes of a CSI sequence
+	ansiIntermediate                  // in the intermediate bytes of a CSI sequence
+)
+
+// escapeChar is the ESC control character that introduces ANSI escape sequences.
+const escapeChar = 0x1b
+
+// acceptANSI advances the escape sequence state machine with the next rune. A CSI sequence is
+// ESC '[', any parameter bytes (0x30-0x3F), any intermediate bytes (0x20-0x2F), and a final
+// byte (0x40-0x7E). Anything else, including a bare ESC, is rejected.
+// It reports whether the content is still plaintext.
+func (s *scanner) acceptANSI(r rune) bool {
+	switch s.ansi {
+	case ansiNone:
+		s.ansi = ansiEscape
+	case ansiEscape:
+		if r != '[' {
+			s.failed = true
+			return false
+		}
+		s.ansi = ansiParams
+	case ansiParams, ansiIntermediate:
+		switch {
+		case r >= 0x30 && r <= 0x3f && s.ansi == ansiParams:
+		case r >= 0x20 && r <= 0x2f:
+			s.ansi = ansiIntermediate
+		case r >= 0x40 && r <= 0x7e:
+			s.ansi = ansiNone
+			s.sawANSI = true
+		default:
+			s.failed = true
+			return false
+		}
+	}
+	s.runes++
+	return true
+}
@@ -0,0 +1,43 @@
+package isplaintextfile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithRecognizeANSIEscapes(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expected     bool
+		containsANSI bool
+	}{
+		{"colored log line", "\x1b[32mINFO\x1b[0m server started on \x1b[1;34m:8080\x1b[0m\n", true, true},
+		{"cursor movement", "progress\x1b[2K\x1b[1G50%\n", true, true},
+		{"no escapes", "plain log line\n", true, false},
+		{"bare ESC", "before \x1b after\n", false, false},
+		{"non-CSI escape", "before \x1b]0;title\x07 after\n", false, false},
+		{"unterminated sequence", "before \x1b[31", false, false},
+		{"other control character", "\x1b[32mINFO\x1b[0m\x07\n", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithRecognizeANSIEscapes())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.ContainsANSI != tt.containsANSI {
+				t.Errorf("Check().ContainsANSI = %v, want %v", res.ContainsANSI, tt.containsANSI)
+			}
+		})
+	}
+
+	// Without the option any ESC is rejected.
+	if res, _ := Check(strings.NewReader("\x1b[32mINFO\x1b[0m\n")); res.IsPlaintext {
+		t.Errorf("Check() without option = %v, want false", res.IsPlaintext)
+	}
+}
@@ -0,0 +1,38 @@
+package isplaintextfile
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ReadersBatch classifies the content of each reader, checking up to concurrency readers at a
+// time, and returns the results in the same order as the readers. It suits anonymous streams
+// without paths; WalkDir covers files. Every reader is checked even if some fail; the error
+// returned is that of the first failing reader in input order, and its result is the zero Result.
+func ReadersBatch(readers []io.Reader, concurrency int) ([]Result, error) {
+	if concurrency <= 0 {
+		return nil, fmt.Errorf("%w: concurrency must be greater than 0", ErrInvalidLength)
+	}
+
+	results := make([]Result, len(readers))
+	errs := make([]error, len(readers))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, reader := range readers {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			results[i], errs[i] = Check(reader)
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
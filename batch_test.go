@@ -0,0 +1,72 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// trackingReader records the peak number of readers being read at once.
+type trackingReader struct {
+	r      io.Reader
+	active *atomic.Int32
+	peak   *atomic.Int32
+}
+
+func (t *trackingReader) Read(p []byte) (int, error) {
+	n := t.active.Add(1)
+	defer t.active.Add(-1)
+	for {
+		peak := t.peak.Load()
+		if n <= peak || t.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return t.r.Read(p)
+}
+
+func TestReadersBatch(t *testing.T) {
+	contents := []string{"text one\n", "\x00\x01\x02", "text two\n", "\xff\xfe\xfd", "你好\n", "", "\x07bell"}
+	expected := []bool{true, false, true, false, true, true, false}
+
+	var active, peak atomic.Int32
+	readers := make([]io.Reader, len(contents))
+	for i, content := range contents {
+		readers[i] = &trackingReader{r: strings.NewReader(content), active: &active, peak: &peak}
+	}
+
+	results, err := ReadersBatch(readers, 2)
+	if err != nil {
+		t.Fatalf("ReadersBatch() error: %v", err)
+	}
+	if len(results) != len(contents) {
+		t.Fatalf("ReadersBatch() returned %d results, want %d", len(results), len(contents))
+	}
+	for i, res := range results {
+		if res.IsPlaintext != expected[i] {
+			t.Errorf("ReadersBatch()[%d] (%q) = %v, want %v", i, contents[i], res.IsPlaintext, expected[i])
+		}
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("ReadersBatch() read %d readers at once, want at most 2", p)
+	}
+
+	// The first error in input order is returned along with the other results.
+	errBroken := errors.New("broken")
+	results, err = ReadersBatch([]io.Reader{strings.NewReader("ok\n"), iotest.ErrReader(errBroken)}, 4)
+	if !errors.Is(err, errBroken) {
+		t.Errorf("ReadersBatch() error = %v, want %v", err, errBroken)
+	}
+	if len(results) != 2 || !results[0].IsPlaintext {
+		t.Errorf("ReadersBatch() = %+v, want the first reader classified", results)
+	}
+
+	if _, err := ReadersBatch(nil, 0); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("ReadersBatch() with zero concurrency error = %v, want %v", err, ErrInvalidLength)
+	}
+}
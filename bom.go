@@ -0,0 +1,62 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"io"
+)
+
+// maxBOMSize is the length of the longest byte order mark, that of UTF-32.
+const maxBOMSize = 4
+
+// bomStripReader removes a byte order mark from the start of the wrapped reader.
+type bomStripReader struct {
+	r       io.Reader
+	checked bool
+
+	// err is an error that interrupted the read of the start of the content, returned once the
+	// bytes read before it have been.
+	err error
+}
+
+// BOMStripReader returns a reader that yields the content of r with a leading UTF-8, UTF-16, or
+// UTF-32 byte order mark removed, for parsers such as encoding/json and encoding/csv that reject
+// one. Only a mark at the very start is removed; the rest of the content is passed through
+// untouched. The first Read waits for up to four bytes to recognize the mark.
+func BOMStripReader(r io.Reader) io.Reader {
+	return &bomStripReader{r: r}
+}
+
+// Read implements io.Reader.
+func (b *bomStripReader) Read(p []byte) (int, error) {
+	if !b.checked {
+		head := make([]byte, maxBOMSize)
+		n, err := io.ReadFull(b.r, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			if n == 0 {
+				// Nothing was consumed, so the next Read looks for the mark again.
+				return 0, err
+			}
+			b.err = err
+		}
+		b.checked = true
+
+		head = head[:n]
+		for _, m := range byteOrderMarks {
+			if bytes.HasPrefix(head, m.bom) {
+				head = head[len(m.bom):]
+				break
+			}
+		}
+		if b.err != nil {
+			b.r = bytes.NewReader(head)
+		} else {
+			b.r = io.MultiReader(bytes.NewReader(head), b.r)
+		}
+	}
+
+	n, err := b.r.Read(p)
+	if err == io.EOF && b.err != nil {
+		err = b.err
+	}
+	return n, err
+}
@@ -0,0 +1,57 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBOMStripReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"UTF-8 BOM", "\xef\xbb\xbf{\"a\": 1}\n", "{\"a\": 1}\n"},
+		{"UTF-16LE BOM", "\xff\xfeh\x00i\x00", "h\x00i\x00"},
+		{"UTF-16BE BOM", "\xfe\xff\x00h\x00i", "\x00h\x00i"},
+		{"UTF-32LE BOM", "\xff\xfe\x00\x00h\x00\x00\x00", "h\x00\x00\x00"},
+		{"UTF-32BE BOM", "\x00\x00\xfe\xff\x00\x00\x00h", "\x00\x00\x00h"},
+		{"no BOM", "a,b,c\n1,2,3\n", "a,b,c\n1,2,3\n"},
+		{"interior BOM kept", "a\xef\xbb\xbfb", "a\xef\xbb\xbfb"},
+		{"BOM only", "\xef\xbb\xbf", ""},
+		{"short content", "ab", "ab"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A one-byte reader splits the BOM across reads.
+			for _, r := range []io.Reader{strings.NewReader(tt.content), iotest.OneByteReader(strings.NewReader(tt.content))} {
+				got, err := io.ReadAll(BOMStripReader(r))
+				if err != nil {
+					t.Fatalf("ReadAll() error: %v", err)
+				}
+				if string(got) != tt.expected {
+					t.Errorf("BOMStripReader() = %q, want %q", got, tt.expected)
+				}
+			}
+		})
+	}
+
+	// Read errors are passed through.
+	errBroken := errors.New("broken")
+	if _, err := io.ReadAll(BOMStripReader(iotest.ErrReader(errBroken))); !errors.Is(err, errBroken) {
+		t.Errorf("ReadAll() error = %v, want %v", err, errBroken)
+	}
+
+	// Bytes read before an error are still returned, with the BOM stripped.
+	for content, expected := range map[string]string{"\xef\xbb\xbf": "", "ab": "ab"} {
+		got, err := io.ReadAll(BOMStripReader(io.MultiReader(strings.NewReader(content), iotest.ErrReader(errBroken))))
+		if string(got) != expected || !errors.Is(err, errBroken) {
+			t.Errorf("ReadAll() of %q before an error = %q, %v, want %q, %v", content, got, err, expected, errBroken)
+		}
+	}
+}
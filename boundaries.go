@@ -0,0 +1,98 @@
+package isplaintextfile
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// minTextRun is the number of consecutive text runes that end a non-text region in
+// DetectBoundaries. Shorter runs, such as the printable bytes scattered through binary data, stay
+// part of the non-text region.
+const minTextRun = 16
+
+// DetectBoundaries reads the reader to the end in a single pass and returns the byte offsets at
+// which its content switches between text and non-text, for tools that store text and binary
+// regions separately. A rune is text if it is valid UTF-8 allowed under the default policy.
+//
+// Like the rest of the package, a single non-text byte ends a text region, at the offset of that
+// byte. A non-text region only ends at a run of at least 16 consecutive text runes, at the offset
+// of the start of the run, so stray printable bytes inside binary data are not reported as
+// regions of their own. The first region starts at offset 0, which is not reported.
+func DetectBoundaries(reader io.Reader) ([]int64, error) {
+	o := buildOptions(nil)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	d := boundaryDetector{s: scanner{opts: o}}
+	buffer := make([]byte, defaultBufferSize)
+	carry := 0
+	for {
+		n, err := reader.Read(buffer[carry:])
+		data := buffer[:carry+n]
+		cut := len(data)
+		if err == nil {
+			// Keep a rune split by the read for the next one.
+			cut = runeBoundary(data)
+		}
+		d.feed(data[:cut])
+		carry = copy(buffer, data[cut:])
+
+		if err == io.EOF {
+			return d.boundaries, nil
+		}
+		if err != nil {
+			return d.boundaries, err
+		}
+	}
+}
+
+// boundaryDetector classifies content rune by rune and records where its regions change.
+type boundaryDetector struct {
+	s      scanner
+	offset int64
+
+	// started reports that the first rune has been seen, and text the class of the current region.
+	started bool
+	text    bool
+
+	// runStart is the offset of the run of text runes in a non-text region, and runLen its length.
+	runStart int64
+	runLen   int
+
+	boundaries []int64
+}
+
+// feed classifies the runes of data, which must not end in the middle of a valid rune unless the
+// content ends there. An invalid byte is a non-text rune of its own.
+func (d *boundaryDetector) feed(data []byte) {
+	for pos := 0; pos < len(data); {
+		r, size := utf8.DecodeRune(data[pos:])
+		d.next(!(r == utf8.RuneError && size == 1) && d.s.allowed(r), size)
+		pos += size
+	}
+}
+
+// next records the class of the rune of size bytes at the current offset.
+func (d *boundaryDetector) next(text bool, size int) {
+	switch {
+	case !d.started:
+		d.started = true
+		d.text = text
+	case text == d.text:
+		d.runLen = 0
+	case !text:
+		d.boundaries = append(d.boundaries, d.offset)
+		d.text = false
+	default:
+		if d.runLen == 0 {
+			d.runStart = d.offset
+		}
+		d.runLen++
+		if d.runLen >= minTextRun {
+			d.boundaries = append(d.boundaries, d.runStart)
+			d.text = true
+			d.runLen = 0
+		}
+	}
+	d.offset += int64(size)
+}
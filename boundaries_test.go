@@ -0,0 +1,50 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDetectBoundaries(t *testing.T) {
+	text := []byte("Some plain text before and after the binary region.\n")
+	// Binary data with a few printable bytes scattered through it.
+	binary := bytes.Repeat([]byte{0x00, 0x01, 'A', 0xff, 0xfe, 'b', 'c', 0x89}, 32)
+	textLen, binaryLen := int64(len(text)), int64(len(binary))
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected []int64
+	}{
+		{"text, binary, text", bytes.Join([][]byte{text, binary, text}, nil), []int64{textLen, textLen + binaryLen}},
+		{"binary, text", bytes.Join([][]byte{binary, text}, nil), []int64{binaryLen}},
+		{"multibyte text around binary", []byte("你好，世界！\x00\x01\x02\x03你好，世界！你好，世界！你好，世界！"), []int64{18, 22}},
+		{"short text run in binary", bytes.Join([][]byte{binary, []byte("short"), binary}, nil), nil},
+		{"text only", text, nil},
+		{"binary only", binary, nil},
+		{"empty", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectBoundaries(bytes.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("DetectBoundaries() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("DetectBoundaries() = %v, want %v", got, tt.expected)
+			}
+
+			// Runes split across reads are classified whole.
+			got, err = DetectBoundaries(iotest.OneByteReader(bytes.NewReader(tt.content)))
+			if err != nil {
+				t.Errorf("DetectBoundaries() with one-byte reads error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("DetectBoundaries() with one-byte reads = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
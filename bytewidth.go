@@ -0,0 +1,43 @@
+package isplaintextfile
+
+// ByteWidth is the narrowest character storage that can hold every character of some text.
+type ByteWidth int
+
+const (
+	// UnknownWidth means the width was not determined.
+	UnknownWidth ByteWidth = iota
+
+	// SevenBit means every character is ASCII.
+	SevenBit
+
+	// EightBit means every character is in the Latin-1 range, up to U+00FF, so the text fits in a
+	// single-byte encoding such as ISO-8859-1.
+	EightBit
+
+	// Multibyte means some character is beyond U+00FF and needs a multibyte encoding.
+	Multibyte
+)
+
+// String returns the name of the width.
+func (w ByteWidth) String() string {
+	switch w {
+	case SevenBit:
+		return "SevenBit"
+	case EightBit:
+		return "EightBit"
+	case Multibyte:
+		return "Multibyte"
+	}
+	return "Unknown"
+}
+
+// byteWidth returns the width needed for text whose largest rune is maxRune.
+func byteWidth(maxRune rune) ByteWidth {
+	switch {
+	case maxRune <= 0x7f:
+		return SevenBit
+	case maxRune <= 0xff:
+		return EightBit
+	}
+	return Multibyte
+}
@@ -0,0 +1,133 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+)
+
+// Cat is a coarse content category.
+type Cat int
+
+const (
+	// Binary is content that is not plaintext.
+	Binary Cat = iota
+
+	// PlainText is plaintext with no markup or code signals, such as prose.
+	PlainText
+
+	// SourceCode is plaintext that looks like program source.
+	SourceCode
+
+	// MarkupOrStructured is plaintext that looks like markup or a structured data format such as HTML, XML, or JSON.
+	MarkupOrStructured
+
+	// StructuredDocument is a document format such as PDF or RTF that begins with ASCII text but
+	// is not meant to be read as text, whether or not its content is plaintext.
+	StructuredDocument
+)
+
+// String returns the name of the category.
+func (c Cat) String() string {
+	switch c {
+	case Binary:
+		return "Binary"
+	case PlainText:
+		return "PlainText"
+	case SourceCode:
+		return "SourceCode"
+	case MarkupOrStructured:
+		return "MarkupOrStructured"
+	case StructuredDocument:
+		return "StructuredDocument"
+	}
+	return "Unknown"
+}
+
+var (
+	// markupTagPattern matches an opening, closing, declaration, or processing instruction tag.
+	markupTagPattern = regexp.MustCompile(`<[A-Za-z!?/][^<>]*>`)
+
+	// codeKeywordPattern matches a keyword common across programming languages in code position:
+	// at the start of a line, followed by an identifier, a literal, or punctuation. Keywords in the
+	// middle of a sentence, such as "return" in prose, do not match.
+	codeKeywordPattern = regexp.MustCompile(`(?m)^[ \t]*(func|def|class|import|return|package|public|private|const|var|let|function)(?:[ \t]+[A-Za-z_"'(\[{]|[;(])`)
+
+	// codeTokens are constructs common across programming languages that do not occur in prose.
+	codeTokens = [][]byte{[]byte("#include"), []byte("=>"), []byte("#!/")}
+
+	// documentSignatures are the leading bytes of document formats classified as StructuredDocument.
+	documentSignatures = [][]byte{[]byte("%PDF-"), []byte(`{\rtf`)}
+)
+
+// documentSignatureSize is the length of the longest document signature.
+const documentSignatureSize = 5
+
+// isStructuredDocument reports whether the content starting with head is a document format such
+// as PDF or RTF.
+func isStructuredDocument(head []byte) bool {
+	for _, sig := range documentSignatures {
+		if bytes.HasPrefix(head, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// Category classifies the content of the reader as Binary or, for plaintext, refines it to
+// PlainText, SourceCode, or MarkupOrStructured. Content starting with the signature of a document
+// format such as PDF or RTF is StructuredDocument either way. The refinement is a lightweight heuristic over
+// the first few kilobytes of the content, based on tags, enclosing braces, and keywords at the
+// start of lines and other common code tokens; it is a hint rather than a reliable format detector.
+func Category(reader io.Reader) (Cat, error) {
+	sample, full, err := sniff(reader)
+	if err != nil {
+		return Binary, err
+	}
+
+	res, err := check(full, nil, buildOptions(nil))
+	if err != nil {
+		return Binary, err
+	}
+	if res.Classification == StructuredDocument || !res.IsPlaintext {
+		return res.Classification, nil
+	}
+	return categorize(sample), nil
+}
+
+// categorize applies the category heuristics to a sample of plaintext content.
+func categorize(sample []byte) Cat {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(sample, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return PlainText
+	}
+
+	// Markup starts with a tag and contains several of them.
+	if trimmed[0] == '<' && len(markupTagPattern.FindAllIndex(trimmed, 2)) == 2 {
+		return MarkupOrStructured
+	}
+
+	// JSON documents are enclosed in braces or brackets.
+	first, last := trimmed[0], trimmed[len(trimmed)-1]
+	if (first == '{' && last == '}') || (first == '[' && last == ']') {
+		return MarkupOrStructured
+	}
+
+	keywords := make(map[string]bool)
+	for _, m := range codeKeywordPattern.FindAllSubmatch(trimmed, -1) {
+		keywords[string(m[1])] = true
+	}
+	score := len(keywords)
+	for _, token := range codeTokens {
+		if bytes.Contains(trimmed, token) {
+			score++
+		}
+	}
+	if bytes.ContainsAny(trimmed, "{}") && bytes.Contains(trimmed, []byte(";")) {
+		score++
+	}
+	if score >= 2 {
+		return SourceCode
+	}
+	return PlainText
+}
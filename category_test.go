@@ -0,0 +1,39 @@
+package isplaintextfile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Cat
+	}{
+		{"HTML snippet", "<!DOCTYPE html>\n<html><body><p>Hello</p></body></html>\n", MarkupOrStructured},
+		{"XML document", "<?xml version=\"1.0\"?>\n<note><to>Tove</to></note>\n", MarkupOrStructured},
+		{"JSON snippet", "{\n  \"name\": \"example\",\n  \"values\": [1, 2, 3]\n}\n", MarkupOrStructured},
+		{"Go source", "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n", SourceCode},
+		{"JavaScript source", "const add = (a, b) => {\n  return a + b;\n};\n", SourceCode},
+		{"prose", "It was a bright cold day in April, and the clocks were striking thirteen.\n", PlainText},
+		{"prose with code keywords", "I will return home. Let me import some tea.\n", PlainText},
+		{"Python source", "import os\n\ndef main():\n    return os.getcwd()\n", SourceCode},
+		{"empty", "", PlainText},
+		{"binary", "\x00\x01\x02\x03", Binary},
+		{"PDF", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n", StructuredDocument},
+		{"RTF", "{\\rtf1\\ansi Hello}\n", StructuredDocument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Category(strings.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("Category() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("Category() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package isplaintextfile
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Chunks reads the stream in blocks of chunkSize bytes and classifies each block independently,
+// returning one verdict per block so that callers can coarsely locate binary regions. The last
+// block may be shorter. Block boundaries are aligned to rune boundaries: when a block would end
+// in the middle of a multibyte rune, that rune is moved to the start of the next block instead.
+// chunkSize must be at least utf8.UTFMax bytes.
+func Chunks(reader io.Reader, chunkSize int) ([]bool, error) {
+	if chunkSize < utf8.UTFMax {
+		return nil, fmt.Errorf("%w: chunkSize must be at least 4", ErrInvalidLength)
+	}
+
+	o := buildOptions(nil)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	buffer := make([]byte, chunkSize)
+	carry := 0
+
+	var verdicts []bool
+	for {
+		n, err := io.ReadFull(reader, buffer[carry:])
+		data := buffer[:carry+n]
+		final := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !final {
+			return verdicts, err
+		}
+		if len(data) == 0 {
+			return verdicts, nil
+		}
+
+		cut := len(data)
+		if !final {
+			cut = runeBoundary(data)
+		}
+
+		s := scanner{opts: o}
+		s.feed(data[:cut])
+		verdicts = append(verdicts, s.finish())
+
+		if final {
+			return verdicts, nil
+		}
+		carry = copy(buffer, data[cut:])
+	}
+}
+
+// runeBoundary returns the length of the longest prefix of data that does not end in the
+// middle of a multibyte rune.
+func runeBoundary(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,40 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestChunks(t *testing.T) {
+	text := bytes.Repeat([]byte("text line\n"), 10)
+	binary := bytes.Repeat([]byte{0x00, 0xff, 0x01, 0x02, 0x03}, 20)
+
+	tests := []struct {
+		name      string
+		content   []byte
+		chunkSize int
+		expected  []bool
+	}{
+		{"text binary text", slices.Concat(text, binary, text), 100, []bool{true, false, true}},
+		{"trailing short chunk", slices.Concat(text, text[:30]), 100, []bool{true, true}},
+		{"rune across boundary", []byte("abcdefghi👋jklmnopqr"), 10, []bool{true, true, true}},
+		{"empty", nil, 100, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Chunks(bytes.NewReader(tt.content), tt.chunkSize)
+			if err != nil {
+				t.Errorf("Chunks() error: %v", err)
+			}
+			if !slices.Equal(res, tt.expected) {
+				t.Errorf("Chunks() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	if _, err := Chunks(bytes.NewReader(text), 2); err == nil {
+		t.Errorf("Chunks() with chunkSize 2 error = nil, want error")
+	}
+}
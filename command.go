@@ -0,0 +1,39 @@
+package isplaintextfile
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// Command starts cmd and checks if its standard output is plaintext as it streams, returning the
+// verdict once the command has completed. The output is drained after a verdict is reached so the
+// command is never blocked writing to the pipe. cmd.Stdout must not be set.
+//
+// If the command cannot be started no verdict is reached and false is returned with the error. If
+// it exits unsuccessfully the verdict on the output it did produce is returned with the error,
+// which wraps the *exec.ExitError.
+func Command(cmd *exec.Cmd, opts ...Option) (bool, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return false, fmt.Errorf("starting command: %w", err)
+	}
+
+	res, checkErr := Check(stdout, opts...)
+	// Drain whatever the check did not read, as Wait closes the pipe and the command would
+	// otherwise fail writing to it.
+	_, drainErr := io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return res.IsPlaintext, fmt.Errorf("running command: %w", err)
+	}
+	if checkErr != nil {
+		return res.IsPlaintext, checkErr
+	}
+	if drainErr != nil {
+		return res.IsPlaintext, fmt.Errorf("reading command output: %w", drainErr)
+	}
+	return res.IsPlaintext, nil
+}
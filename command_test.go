@@ -0,0 +1,50 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	tests := []struct {
+		name     string
+		script   string
+		expected bool
+		exitErr  bool
+	}{
+		{"echo hello", "echo hello", true, false},
+		{"binary output", `printf '\000\001\002\377'`, false, false},
+		{"binary after text", `echo hello; printf '\000\001'; echo world`, false, false},
+		{"large binary output", `head -c 1000000 /dev/zero`, false, false},
+		{"no output", "true", true, false},
+		{"failing command", "echo partial; exit 3", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Command(exec.Command("sh", "-c", tt.script))
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) != tt.exitErr {
+				t.Errorf("Command() error = %v, want exit error %v", err, tt.exitErr)
+			}
+			if !tt.exitErr && err != nil {
+				t.Errorf("Command() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("Command() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommandNotStarted(t *testing.T) {
+	res, err := Command(exec.Command("isplaintextfile-command-that-does-not-exist"))
+	if err == nil || res {
+		t.Errorf("Command() = %v, %v, want false with an error", res, err)
+	}
+}
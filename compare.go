@@ -0,0 +1,28 @@
+package isplaintextfile
+
+import (
+	"io"
+)
+
+// Compare classifies the content of the reader twice, once with each set of options, and
+// returns both results so callers can see how a policy change affects the content before
+// rolling it out. The reader is seeked to the start before each run.
+func Compare(reader io.ReadSeeker, a, b []Option) (Result, Result, error) {
+	resA, err := checkFromStart(reader, a)
+	if err != nil {
+		return Result{}, Result{}, err
+	}
+	resB, err := checkFromStart(reader, b)
+	if err != nil {
+		return Result{}, Result{}, err
+	}
+	return resA, resB, nil
+}
+
+// checkFromStart seeks the reader to the start and classifies its content.
+func checkFromStart(reader io.ReadSeeker, opts []Option) (Result, error) {
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return Result{}, err
+	}
+	return check(reader, nil, buildOptions(opts))
+}
@@ -0,0 +1,33 @@
+package isplaintextfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pages.txt")
+	if err := os.WriteFile(path, []byte("page one\n\fpage two\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open temp file: %v", err)
+	}
+	defer file.Close()
+
+	strict, lenient, err := Compare(file, nil, []Option{WithAllowedControlChars('\f')})
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if strict.IsPlaintext {
+		t.Errorf("Compare() default IsPlaintext = %v, want false", strict.IsPlaintext)
+	}
+	if !lenient.IsPlaintext {
+		t.Errorf("Compare() WithAllowedControlChars IsPlaintext = %v, want true", lenient.IsPlaintext)
+	}
+	if lenient.RuneCount != 19 {
+		t.Errorf("Compare() WithAllowedControlChars RuneCount = %d, want 19", lenient.RuneCount)
+	}
+}
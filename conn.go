@@ -0,0 +1,56 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// ErrConnTimeout is returned by Conn when the read deadline passes before the preview is complete.
+var ErrConnTimeout = errors.New("connection read timed out")
+
+// Conn checks if up to maxKB kilobytes received on the connection are plaintext, such as to tell
+// a text protocol from a binary one on an incoming connection. Reading stops at the preview
+// limit, so nothing beyond it is consumed from the connection, but the bytes within the preview
+// are; wrap the connection to replay them if the protocol handler needs them.
+//
+// Reads fail once the deadline passes, unless it is the zero time; the deadline is cleared before
+// returning. If it passes first, the verdict covers the bytes received so far and the error wraps
+// both ErrConnTimeout and os.ErrDeadlineExceeded.
+func Conn(conn net.Conn, maxKB int, deadline time.Time) (bool, error) {
+	if err := validatePreviewKB(maxKB); err != nil {
+		return true, err
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return false, err
+	}
+	defer conn.SetReadDeadline(time.Time{})
+
+	tr := &timeoutReader{r: conn}
+	res, err := isPlaintextFromReader(limitPreview(tr, maxKB), nil)
+	if err != nil {
+		return false, err
+	}
+	if tr.timeout != nil {
+		return res, fmt.Errorf("%w: %w", ErrConnTimeout, tr.timeout)
+	}
+	return res, nil
+}
+
+// timeoutReader ends the content at the first read that times out, recording its error.
+type timeoutReader struct {
+	r       io.Reader
+	timeout error
+}
+
+func (t *timeoutReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		t.timeout = err
+		return n, io.EOF
+	}
+	return n, err
+}
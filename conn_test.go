@@ -0,0 +1,85 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConn(t *testing.T) {
+	text := bytes.Repeat([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"), 100)
+
+	tests := []struct {
+		name        string
+		content     []byte
+		keepOpen    bool
+		expected    bool
+		expectedErr error
+	}{
+		{"text protocol", text[:37], false, true, nil},
+		{"binary protocol", []byte{0x16, 0x03, 0x01, 0x02, 0x00, 0x01, 0x00, 0x01, 0xfc, 0x03, 0x03}, false, false, nil},
+		{"binary after the preview", append(append([]byte{}, text...), 0x00), false, true, nil},
+		{"multibyte rune cut by the preview", append(bytes.Repeat([]byte("a"), 1023), "\u00e9 and more text"...), false, true, nil},
+		{"silent peer", nil, true, true, ErrConnTimeout},
+		{"text then silence", text[:37], true, true, ErrConnTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer server.Close()
+
+			go func() {
+				_, _ = client.Write(tt.content)
+				if !tt.keepOpen {
+					client.Close()
+				}
+			}()
+			defer client.Close()
+
+			res, err := Conn(server, 1, time.Now().Add(100*time.Millisecond))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Conn() error = %v, want %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+				t.Errorf("Conn() error = %v, want %v", err, os.ErrDeadlineExceeded)
+			}
+			if res != tt.expected {
+				t.Errorf("Conn() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConnStopsAtPreview(t *testing.T) {
+	content := append(bytes.Repeat([]byte("A"), 3000), 0x00)
+	server, client := net.Pipe()
+	defer server.Close()
+
+	go func() {
+		_, _ = client.Write(content)
+		client.Close()
+	}()
+
+	res, err := Conn(server, 1, time.Now().Add(time.Second))
+	if err != nil || !res {
+		t.Errorf("Conn() = %v, %v, want true", res, err)
+	}
+
+	// The rest of the content is still available, with the deadline cleared.
+	rest, err := io.ReadAll(server)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error: %v", err)
+	}
+	if !bytes.Equal(rest, content[1024:]) {
+		t.Errorf("remaining content is %d bytes, want %d", len(rest), len(content)-1024)
+	}
+
+	if _, err := Conn(server, 0, time.Time{}); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Conn() error = %v, want %v", err, ErrInvalidLength)
+	}
+}
@@ -0,0 +1,14 @@
+package isplaintextfile
+
+import (
+	"net/http"
+)
+
+// StdlibSniff returns the content type reported by http.DetectContentType alongside this
+// package's verdict on whether data is plaintext. The two can disagree: the standard library
+// sniffs only the first 512 bytes and reports text/plain for content with some control
+// characters, while isText examines all of data under the stricter policy. When they disagree,
+// isText is the verdict of this package; contentType is always returned unchanged.
+func StdlibSniff(data []byte) (contentType string, isText bool) {
+	return http.DetectContentType(data), isBufferPlaintext(data)
+}
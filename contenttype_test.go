@@ -0,0 +1,37 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStdlibSniff(t *testing.T) {
+	text := bytes.Repeat([]byte("a"), 512)
+
+	tests := []struct {
+		name                string
+		content             []byte
+		expectedContentType string
+		expectedText        bool
+	}{
+		{"plain text", []byte("Hello, World!\n"), "text/plain; charset=utf-8", true},
+		{"binary", []byte{0x00, 0x01, 0x02, 0x03}, "application/octet-stream", false},
+		{"HTML", []byte("<!DOCTYPE html><html></html>"), "text/html; charset=utf-8", true},
+		{"form feed", []byte("page one\fpage two\n"), "text/plain; charset=utf-8", false},
+		{"binary before sniff boundary", append(text[:511:511], 0x00), "application/octet-stream", false},
+		{"binary after sniff boundary", append(text[:512:512], 0x00), "text/plain; charset=utf-8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, isText := StdlibSniff(tt.content)
+			if !strings.EqualFold(contentType, tt.expectedContentType) {
+				t.Errorf("StdlibSniff() contentType = %q, want %q", contentType, tt.expectedContentType)
+			}
+			if isText != tt.expectedText {
+				t.Errorf("StdlibSniff() isText = %v, want %v", isText, tt.expectedText)
+			}
+		})
+	}
+}
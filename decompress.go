@@ -0,0 +1,129 @@
+package isplaintextfile
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// ErrDecompressionLimitExceeded is returned when compressed content expands beyond the limit set
+// by WithMaxDecompressedBytes.
+var ErrDecompressionLimitExceeded = errors.New("decompression limit exceeded")
+
+// Magic bytes at the start of compressed streams.
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// bzip2HeaderLen is the length of a bzip2 stream header: the magic, the block size digit, and the
+// magic of the first block or of the end of the stream.
+const bzip2HeaderLen = 10
+
+// decompress sniffs the magic bytes at the start of the reader and returns a reader that yields
+// the decompressed content, failing with ErrDecompressionLimitExceeded after limit bytes if limit
+// is positive. Content that is not compressed is returned as is. The returned reader must be
+// closed to release the decompressor.
+func decompress(reader io.Reader, limit int64) (io.ReadCloser, error) {
+	br := bufio.NewReader(reader)
+	magic, err := br.Peek(bzip2HeaderLen)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	var decompressed io.ReadCloser
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		if decompressed, err = gzip.NewReader(br); err != nil {
+			return nil, err
+		}
+	case isBzip2Header(magic):
+		decompressed = io.NopCloser(bzip2.NewReader(br))
+	case bytes.HasPrefix(magic, xzMagic):
+		xr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		decompressed = io.NopCloser(xr)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		decompressed = zr.IOReadCloser()
+	default:
+		return io.NopCloser(br), nil
+	}
+
+	if limit > 0 {
+		decompressed = &decompressionLimit{ReadCloser: decompressed, remaining: limit}
+	}
+	return decompressed, nil
+}
+
+// isBzip2Header reports whether header starts a bzip2 stream. Text can start with "BZh", so the
+// header is only accepted if the bzip2 decompressor reads past it without a structural error.
+func isBzip2Header(header []byte) bool {
+	if len(header) < bzip2HeaderLen || !bytes.HasPrefix(header, bzip2Magic) {
+		return false
+	}
+	if level := header[len(bzip2Magic)]; level < '1' || level > '9' {
+		return false
+	}
+	_, err := bzip2.NewReader(bytes.NewReader(header)).Read(make([]byte, 1))
+	var structuralErr bzip2.StructuralError
+	return !errors.As(err, &structuralErr)
+}
+
+// decompressionLimit reads decompressed content until remaining bytes have been read, and fails
+// with ErrDecompressionLimitExceeded if there is more.
+type decompressionLimit struct {
+	io.ReadCloser
+	remaining int64
+}
+
+// Read implements io.Reader. One byte beyond the limit is read to tell whether the content ends
+// exactly at the limit.
+func (d *decompressionLimit) Read(p []byte) (int, error) {
+	if int64(len(p)) > d.remaining+1 {
+		p = p[:d.remaining+1]
+	}
+	n, err := d.ReadCloser.Read(p)
+	if int64(n) > d.remaining {
+		n = int(d.remaining)
+		d.remaining = 0
+		return n, ErrDecompressionLimitExceeded
+	}
+	d.remaining -= int64(n)
+	return n, err
+}
+
+// FileAutoDecompress opens the file at the given path and checks if its logical content is
+// plaintext. Files compressed with gzip, bzip2, xz, or zstd are detected by their magic bytes and
+// decompressed before the check; other files are checked directly. Use WithMaxDecompressedBytes
+// to guard against decompression bombs.
+func FileAutoDecompress(path string, opts ...Option) (bool, error) {
+	o := buildOptions(opts)
+	file, closeFile, err := o.openFile(path)
+	if err != nil {
+		return false, err
+	}
+	defer closeFile()
+
+	reader, err := decompress(file, o.MaxDecompressedBytes)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+
+	res, err := check(reader, nil, o)
+	return res.IsPlaintext, err
+}
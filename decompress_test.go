@@ -0,0 +1,141 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// gzipBytes compresses content with gzip.
+func gzipBytes(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// xzBytes compresses content with xz.
+func xzBytes(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw, err := xz.NewWriter(&buf)
+	if err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if _, err := zw.Write(content); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// zstdBytes compresses content with zstd.
+func zstdBytes(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	defer zw.Close()
+	return zw.EncodeAll(content, nil)
+}
+
+func TestFileAutoDecompress(t *testing.T) {
+	text := bytes.Repeat([]byte("2024-01-01 INFO request served\n"), 100)
+	binary := []byte{0x00, 0x01, 0x02, 0x03}
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"gzip text", gzipBytes(t, text), true},
+		{"gzip binary", gzipBytes(t, binary), false},
+		{"xz text", xzBytes(t, text), true},
+		{"xz binary", xzBytes(t, binary), false},
+		{"zstd text", zstdBytes(t, text), true},
+		{"zstd binary", zstdBytes(t, binary), false},
+		{"text starting with the bzip2 magic", []byte("BZh is a fine start for prose\n"), true},
+		{"text starting with a bzip2 header", []byte("BZh9 is not a block magic\n"), true},
+		{"uncompressed text", text, true},
+		{"uncompressed binary", binary, false},
+		{"empty", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file")
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatalf("Failed to write temp file: %v", err)
+			}
+
+			res, err := FileAutoDecompress(path)
+			if err != nil {
+				t.Errorf("FileAutoDecompress() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("FileAutoDecompress() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWithMaxDecompressedBytes(t *testing.T) {
+	// A few kilobytes of gzip that expand to 10MB.
+	bomb := gzipBytes(t, bytes.Repeat([]byte("a"), 10<<20))
+	text := []byte("small log line\n")
+
+	tests := []struct {
+		name        string
+		content     []byte
+		expected    bool
+		expectedErr error
+	}{
+		{"expands past the limit", bomb, false, ErrDecompressionLimitExceeded},
+		{"zstd expands past the limit", zstdBytes(t, bytes.Repeat([]byte("a"), 10<<20)), false, ErrDecompressionLimitExceeded},
+		{"within the limit", gzipBytes(t, text), true, nil},
+		{"exactly the limit", gzipBytes(t, bytes.Repeat([]byte("a"), 1024)), true, nil},
+		{"binary verdict within the limit", gzipBytes(t, append([]byte{0x00}, bytes.Repeat([]byte("a"), 10<<20)...)), false, nil},
+		{"uncompressed content is not limited", bytes.Repeat([]byte("a"), 4096), true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file")
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatalf("Failed to write temp file: %v", err)
+			}
+
+			res, err := FileAutoDecompress(path, WithMaxDecompressedBytes(1024))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("FileAutoDecompress() error = %v, want %v", err, tt.expectedErr)
+			}
+			if res != tt.expected {
+				t.Errorf("FileAutoDecompress() = %v, want %v", res, tt.expected)
+			}
+
+			// Best effort does not accept what the limit stopped.
+			res, err = FileAutoDecompress(path, WithMaxDecompressedBytes(1024), WithBestEffortOnError())
+			if !errors.Is(err, tt.expectedErr) || res != tt.expected {
+				t.Errorf("FileAutoDecompress() with best effort = %v, %v, want %v, %v", res, err, tt.expected, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package isplaintextfile
+
+// delimiters are the field separators recognized by WithDetectDelimited, in order of preference
+// when more than one splits every line into the same number of fields.
+var delimiters = [...]rune{',', '\t', '|'}
+
+// minDelimitedLines is the fewest lines with delimiters for content to be considered delimited.
+const minDelimitedLines = 2
+
+// delimitedStats counts the delimiters outside quoted fields on each line, and whether the counts
+// have been the same on every line.
+type delimitedStats struct {
+	// quoted reports that the current position is inside a double-quoted field, where delimiters
+	// and line breaks are part of the field.
+	quoted bool
+
+	// open reports that the current line has content; empty lines are skipped.
+	open bool
+
+	// counts holds the delimiters on the current line, and expected those on the first line.
+	// inconsistent marks delimiters whose count has differed between lines.
+	counts       [len(delimiters)]int
+	expected     [len(delimiters)]int
+	inconsistent [len(delimiters)]bool
+	lines        int
+}
+
+// next updates the statistics for the next rune.
+func (st *delimitedStats) next(r rune) {
+	switch {
+	case r == '"':
+		st.quoted = !st.quoted
+	case st.quoted:
+	case r == '\n':
+		st.endLine()
+		return
+	case r == '\r':
+		return
+	default:
+		for i, d := range delimiters {
+			if r == d {
+				st.counts[i]++
+			}
+		}
+	}
+	st.open = true
+}
+
+// endLine compares the delimiters counted on the line that just ended with the first line.
+func (st *delimitedStats) endLine() {
+	if !st.open {
+		return
+	}
+	for i, n := range st.counts {
+		if st.lines == 0 {
+			st.expected[i] = n
+		} else if n != st.expected[i] {
+			st.inconsistent[i] = true
+		}
+		st.counts[i] = 0
+	}
+	st.lines++
+	st.open = false
+}
+
+// delimiter returns the delimiter found the same number of times on every line and the number of
+// columns it makes, or 0 and 0 if there is none. A final line without a line break is included.
+func (st *delimitedStats) delimiter() (rune, int) {
+	final := *st
+	if !final.quoted {
+		final.endLine()
+	}
+	if final.lines < minDelimitedLines {
+		return 0, 0
+	}
+	for i, d := range delimiters {
+		if !final.inconsistent[i] && final.expected[i] > 0 {
+			return d, final.expected[i] + 1
+		}
+	}
+	return 0, 0
+}
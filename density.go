@@ -0,0 +1,37 @@
+package isplaintextfile
+
+// controlDensity tracks which of the most recent bytes of content belong to control characters,
+// over a window of fixed size.
+type controlDensity struct {
+	// window holds whether each byte was part of a control character, as a ring starting at pos.
+	window   []bool
+	pos      int
+	filled   int
+	controls int
+}
+
+// add records size bytes of a rune, which is a control character if control is set.
+func (d *controlDensity) add(control bool, size int) {
+	for i := range size {
+		if d.window[d.pos] {
+			d.controls--
+		}
+		isControl := control && i == 0
+		d.window[d.pos] = isControl
+		if isControl {
+			d.controls++
+		}
+		d.pos = (d.pos + 1) % len(d.window)
+		d.filled = min(d.filled+1, len(d.window))
+	}
+}
+
+// exceeds reports whether the share of control characters among the bytes in the window is above
+// maxRatio. Until the window has filled, it is only checked when final is set, at the end of
+// content shorter than the window.
+func (d *controlDensity) exceeds(maxRatio float64, final bool) bool {
+	if d.filled == 0 || d.filled < len(d.window) && !final {
+		return false
+	}
+	return float64(d.controls)/float64(d.filled) > maxRatio
+}
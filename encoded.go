@@ -0,0 +1,47 @@
+package isplaintextfile
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// ReaderBase64 decodes standard base64 from the reader and checks if the decoded content is
+// plaintext. Line breaks in the encoded stream are ignored. Malformed base64 returns an error.
+func ReaderBase64(reader io.Reader) (bool, error) {
+	res, err := check(base64.NewDecoder(base64.StdEncoding, reader), nil, buildOptions(nil))
+	if err != nil {
+		return false, fmt.Errorf("decoding base64: %w", err)
+	}
+	return res.IsPlaintext, nil
+}
+
+// ReaderHex decodes hexadecimal from the reader and checks if the decoded content is plaintext.
+// Malformed hexadecimal returns an error.
+func ReaderHex(reader io.Reader) (bool, error) {
+	res, err := check(hex.NewDecoder(reader), nil, buildOptions(nil))
+	if err != nil {
+		return false, fmt.Errorf("decoding hex: %w", err)
+	}
+	return res.IsPlaintext, nil
+}
+
+// ReaderWithTransforms applies each transform to the reader in order, such as decrypting, then
+// decompressing, then decoding, and checks if the content of the final stream is plaintext. An
+// error from a transform, or from reading the transformed stream, is wrapped and returned.
+func ReaderWithTransforms(reader io.Reader, transforms ...func(io.Reader) (io.Reader, error)) (bool, error) {
+	for i, transform := range transforms {
+		next, err := transform(reader)
+		if err != nil {
+			return false, fmt.Errorf("transform %d: %w", i, err)
+		}
+		reader = next
+	}
+
+	res, err := check(reader, nil, buildOptions(nil))
+	if err != nil {
+		return false, fmt.Errorf("reading transformed content: %w", err)
+	}
+	return res.IsPlaintext, nil
+}
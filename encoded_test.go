@@ -0,0 +1,112 @@
+package isplaintextfile
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// pngHeader is the signature and start of the IHDR chunk of a PNG file.
+var pngHeader = []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 'I', 'H', 'D', 'R'}
+
+func TestReaderBase64(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoded  string
+		expected bool
+	}{
+		{"hello", base64.StdEncoding.EncodeToString([]byte("hello")), true},
+		{"PNG header", base64.StdEncoding.EncodeToString(pngHeader), false},
+		{"wrapped lines", "aGVsbG8g\nd29ybGQK\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ReaderBase64(strings.NewReader(tt.encoded))
+			if err != nil {
+				t.Errorf("ReaderBase64() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("ReaderBase64() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ReaderBase64(strings.NewReader("not*base64!")); err == nil {
+		t.Errorf("ReaderBase64() with malformed input error = nil, want error")
+	}
+}
+
+func TestReaderHex(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoded  string
+		expected bool
+	}{
+		{"hello", hex.EncodeToString([]byte("hello")), true},
+		{"PNG header", hex.EncodeToString(pngHeader), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ReaderHex(strings.NewReader(tt.encoded))
+			if err != nil {
+				t.Errorf("ReaderHex() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("ReaderHex() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ReaderHex(strings.NewReader("zz")); err == nil {
+		t.Errorf("ReaderHex() with malformed input error = nil, want error")
+	}
+}
+
+func TestReaderWithTransforms(t *testing.T) {
+	decodeBase64 := func(r io.Reader) (io.Reader, error) {
+		return base64.NewDecoder(base64.StdEncoding, r), nil
+	}
+	gunzip := func(r io.Reader) (io.Reader, error) {
+		return gzip.NewReader(r)
+	}
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"text", []byte("hello, world\n"), true},
+		{"binary", pngHeader, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := base64.StdEncoding.EncodeToString(gzipBytes(t, tt.content))
+
+			res, err := ReaderWithTransforms(strings.NewReader(encoded), decodeBase64, gunzip)
+			if err != nil {
+				t.Errorf("ReaderWithTransforms() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("ReaderWithTransforms() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	// Without transforms the reader is checked directly.
+	if res, err := ReaderWithTransforms(strings.NewReader("plain\n")); err != nil || !res {
+		t.Errorf("ReaderWithTransforms() = %v, %v, want true", res, err)
+	}
+
+	// The gzip reader fails on content that is not gzip.
+	encoded := base64.StdEncoding.EncodeToString([]byte("this is definitely not gzip data"))
+	if _, err := ReaderWithTransforms(strings.NewReader(encoded), decodeBase64, gunzip); !errors.Is(err, gzip.ErrHeader) {
+		t.Errorf("ReaderWithTransforms() error = %v, want %v", err, gzip.ErrHeader)
+	}
+}
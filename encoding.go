@@ -0,0 +1,429 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode/utf32"
+	"golang.org/x/text/transform"
+)
+
+// Encoding identifies a character encoding by its preferred MIME or IANA name.
+type Encoding string
+
+const (
+	// Unknown means the encoding could not be determined.
+	Unknown Encoding = ""
+
+	// UTF8 is the UTF-8 encoding, which is validated directly without decoding.
+	UTF8 Encoding = "UTF-8"
+
+	// Latin1 is the ISO-8859-1 encoding.
+	Latin1 Encoding = "ISO-8859-1"
+
+	// EBCDIC is the IBM code page 037 variant of EBCDIC used by US and Canadian mainframes.
+	EBCDIC Encoding = "IBM037"
+
+	// UTF16LE is little-endian UTF-16.
+	UTF16LE Encoding = "UTF-16LE"
+
+	// UTF16BE is big-endian UTF-16.
+	UTF16BE Encoding = "UTF-16BE"
+
+	// UTF32LE is little-endian UTF-32.
+	UTF32LE Encoding = "UTF-32LE"
+
+	// UTF32BE is big-endian UTF-32.
+	UTF32BE Encoding = "UTF-32BE"
+
+	// AutoDetect selects the encoding from the content: a byte order mark if present, otherwise
+	// UTF-16 if NUL bytes fall in the alternating pattern of mostly-ASCII UTF-16 text, otherwise
+	// UTF-8 if the content is valid UTF-8, otherwise Latin1 unless it has bytes that Latin1 decodes
+	// as C1 control characters, in which case it is validated as UTF-8.
+	AutoDetect Encoding = "auto"
+)
+
+// Endianness is the byte order of content in a UTF-16 or UTF-32 encoding.
+type Endianness int
+
+const (
+	// UnknownEndianness means the encoding has no byte order, such as UTF-8.
+	UnknownEndianness Endianness = iota
+
+	// LittleEndian means the least significant byte of each code unit comes first.
+	LittleEndian
+
+	// BigEndian means the most significant byte of each code unit comes first.
+	BigEndian
+)
+
+// String returns the name of the byte order.
+func (e Endianness) String() string {
+	switch e {
+	case LittleEndian:
+		return "LittleEndian"
+	case BigEndian:
+		return "BigEndian"
+	}
+	return "Unknown"
+}
+
+// endianness returns the byte order of the encoding.
+func endianness(enc Encoding) Endianness {
+	switch enc {
+	case UTF16LE, UTF32LE:
+		return LittleEndian
+	case UTF16BE, UTF32BE:
+		return BigEndian
+	}
+	return UnknownEndianness
+}
+
+// Confidence levels reported for encodings chosen by auto-detection.
+const (
+	// bomConfidence is reported when a byte order mark identifies the encoding.
+	bomConfidence = 1.0
+
+	// minUTF16PatternConfidence and maxUTF16PatternConfidence bound the confidence reported
+	// when UTF-16 is chosen from the position of NUL bytes; see detectUTF16Pattern.
+	minUTF16PatternConfidence = 0.5
+	maxUTF16PatternConfidence = 0.99
+
+	// utf8Confidence is reported when the content sample is valid UTF-8.
+	utf8Confidence = 0.9
+
+	// latin1Confidence is reported when Latin1 is chosen because nothing else fits; any byte
+	// sequence decodes as Latin1, so this is also what binary content looks like.
+	latin1Confidence = 0.2
+)
+
+// Byte order marks, longest first so UTF-32LE is not mistaken for UTF-16LE.
+var byteOrderMarks = []struct {
+	bom []byte
+	enc Encoding
+}{
+	{[]byte{0x00, 0x00, 0xfe, 0xff}, UTF32BE},
+	{[]byte{0xff, 0xfe, 0x00, 0x00}, UTF32LE},
+	{[]byte{0xef, 0xbb, 0xbf}, UTF8},
+	{[]byte{0xfe, 0xff}, UTF16BE},
+	{[]byte{0xff, 0xfe}, UTF16LE},
+}
+
+// ErrUnsupportedEncoding is returned when content is to be decoded from an encoding that is not recognized.
+var ErrUnsupportedEncoding = errors.New("unsupported encoding")
+
+// codePageEncoding returns the Encoding for a numeric IBM or Windows code page,
+// or an Encoding named after the IBM code page if the number is not recognized.
+func codePageEncoding(cp int) Encoding {
+	for _, name := range []string{fmt.Sprintf("IBM%03d", cp), fmt.Sprintf("windows-%d", cp)} {
+		if enc, _ := lookupEncoding(name); enc != Unknown {
+			return enc
+		}
+	}
+	return Encoding(fmt.Sprintf("IBM%03d", cp))
+}
+
+// isASCIICompatible reports whether enc decodes every ASCII byte to itself, so that content of
+// only ASCII characters is the same in enc as in UTF-8. Encodings that are not recognized are
+// not.
+func isASCIICompatible(enc Encoding) bool {
+	_, dec := lookupEncoding(string(enc))
+	if dec == nil {
+		return false
+	}
+	ascii := make([]byte, utf8.RuneSelf)
+	for i := range ascii {
+		ascii[i] = byte(i)
+	}
+	decoded, err := dec.NewDecoder().Bytes(ascii)
+	return err == nil && bytes.Equal(decoded, ascii)
+}
+
+// sniffSize is the number of leading bytes sampled to determine the encoding of content.
+const sniffSize = 8 * 1024
+
+// lookupEncoding resolves an encoding name or alias to its canonical Encoding and decoder.
+// A nil decoder is returned for UTF-8, which needs no decoding, and for unrecognized names.
+func lookupEncoding(name string) (Encoding, encoding.Encoding) {
+	switch {
+	case name == "":
+		return Unknown, nil
+	case strings.EqualFold(name, string(UTF32LE)):
+		return UTF32LE, utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM)
+	case strings.EqualFold(name, string(UTF32BE)):
+		return UTF32BE, utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM)
+	}
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		return Unknown, nil
+	}
+	canonical, err := ianaindex.MIME.Name(enc)
+	if err != nil {
+		canonical, err = ianaindex.IANA.Name(enc)
+	}
+	if err != nil {
+		canonical = strings.ToUpper(name)
+	}
+	if Encoding(canonical) == UTF8 {
+		return UTF8, nil
+	}
+	return Encoding(canonical), enc
+}
+
+// sameEncoding reports whether two encoding names or aliases refer to the same encoding.
+func sameEncoding(a, b Encoding) bool {
+	if strings.EqualFold(string(a), string(b)) {
+		return true
+	}
+	ca, _ := lookupEncoding(string(a))
+	cb, _ := lookupEncoding(string(b))
+	return ca != Unknown && ca == cb
+}
+
+// sniff reads up to sniffSize leading bytes from the reader. It returns the sample and a reader
+// that yields the full content, sample included.
+func sniff(reader io.Reader) ([]byte, io.Reader, error) {
+	sample := make([]byte, sniffSize)
+	n, err := io.ReadFull(reader, sample)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+	sample = sample[:n]
+	return sample, io.MultiReader(bytes.NewReader(sample), reader), nil
+}
+
+var (
+	// xmlEncodingPattern matches the encoding pseudo-attribute of an XML declaration.
+	xmlEncodingPattern = regexp.MustCompile(`^<\?xml[^>]*?\sencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+	// htmlCharsetPattern matches a charset in an HTML meta tag, in either the charset
+	// attribute or the content attribute of an http-equiv Content-Type declaration.
+	htmlCharsetPattern = regexp.MustCompile(`(?i)<meta\s[^>]*?charset\s*=\s*["']?([A-Za-z0-9._:-]+)`)
+)
+
+// htmlPrescanSize is how far into the content an HTML meta charset is looked for,
+// matching the prescan length used by browsers.
+const htmlPrescanSize = 1024
+
+// declaredEncoding returns the encoding declared by an XML prolog or HTML meta tag at the
+// start of the sample, or an empty string if there is none.
+func declaredEncoding(sample []byte) string {
+	sample = bytes.TrimPrefix(sample, []byte("\xef\xbb\xbf"))
+	if m := xmlEncodingPattern.FindSubmatch(sample); m != nil {
+		return string(m[1])
+	}
+	if len(sample) > htmlPrescanSize {
+		sample = sample[:htmlPrescanSize]
+	}
+	if m := htmlCharsetPattern.FindSubmatch(sample); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// detection describes the encoding content was decoded from and how confident the choice is.
+// A zero confidence means no detection was performed.
+type detection struct {
+	encoding   Encoding
+	confidence float64
+}
+
+// needsDecoding reports whether the options may require content to be decoded before scanning.
+func needsDecoding(o Options) bool {
+	return o.Encoding != Unknown || o.CharsetDetector != nil || o.RespectDeclaredEncoding
+}
+
+// detectBOM returns the encoding identified by a byte order mark at the start of the sample, or Unknown.
+func detectBOM(sample []byte) Encoding {
+	for _, m := range byteOrderMarks {
+		if bytes.HasPrefix(sample, m.bom) {
+			return m.enc
+		}
+	}
+	return Unknown
+}
+
+// FileEncoding returns the encoding identified by a byte order mark at the start of the file at
+// the given path, or Unknown if it has none. Only the first 4 bytes are read, so this is a cheap
+// metadata check: the content itself is not validated. A file starting with the UTF-16LE byte
+// order mark followed by a NUL character is reported as UTF-32LE, as the two are indistinguishable.
+func FileEncoding(path string) (Encoding, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Unknown, err
+	}
+	defer file.Close()
+
+	var head [4]byte
+	n, err := io.ReadFull(file, head[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return Unknown, err
+	}
+	return detectBOM(head[:n]), nil
+}
+
+// minUTF16Pairs is the fewest byte pairs in which a UTF-16 NUL pattern is recognized.
+const minUTF16Pairs = 4
+
+// detectUTF16Pattern returns the UTF-16 byte order suggested by NUL bytes in the sample and how
+// confident it is, or Unknown. In UTF-16 text made up mostly of ASCII characters, at least half
+// of the high-order bytes are NUL while the low-order bytes almost never are. Binary content full
+// of NULs, such as arrays of small integers, has them in both positions and is not mistaken for
+// UTF-16.
+//
+// The confidence grows with the difference between the shares of NULs in the two positions: text
+// that is entirely ASCII, with every high-order byte NUL and no low-order one, is the clearest
+// case, while text with many characters beyond Latin-1 has fewer NULs to go on and the wrong byte
+// order is more plausible.
+func detectUTF16Pattern(sample []byte) (Encoding, float64) {
+	pairs := len(sample) / 2
+	if pairs < minUTF16Pairs {
+		return Unknown, 0
+	}
+
+	var even, odd int
+	for i := 0; i < pairs*2; i += 2 {
+		if sample[i] == 0 {
+			even++
+		}
+		if sample[i+1] == 0 {
+			odd++
+		}
+	}
+
+	confidence := func(high, low int) float64 {
+		skew := float64(high-low) / float64(pairs)
+		return minUTF16PatternConfidence + (maxUTF16PatternConfidence-minUTF16PatternConfidence)*skew
+	}
+	switch {
+	case odd*2 >= pairs && even*100 <= pairs:
+		return UTF16LE, confidence(odd, even)
+	case even*2 >= pairs && odd*100 <= pairs:
+		return UTF16BE, confidence(even, odd)
+	}
+	return Unknown, 0
+}
+
+// autoDetect chooses an encoding for the sample, which may end in the middle of a rune if it
+// was cut short, and reports how confident the choice is.
+func autoDetect(sample []byte, cut bool) detection {
+	if enc := detectBOM(sample); enc != Unknown {
+		return detection{enc, bomConfidence}
+	}
+	// NULs are valid UTF-8, so UTF-16 must be recognized before UTF-8 validation would accept it
+	// and the scanner would reject it as binary.
+	if enc, confidence := detectUTF16Pattern(sample); enc != Unknown {
+		return detection{enc, confidence}
+	}
+	if cut {
+		sample = sample[:runeBoundary(sample)]
+	}
+	if utf8.Valid(sample) {
+		return detection{UTF8, utf8Confidence}
+	}
+	// Latin-1 decodes bytes 0x80-0x9F as C1 control characters, which text does not contain, so
+	// the encoding of content with them is left undetermined and it is validated as UTF-8.
+	for _, b := range sample {
+		if b >= 0x80 && b <= 0x9f {
+			return detection{encoding: UTF8}
+		}
+	}
+	return detection{Latin1, latin1Confidence}
+}
+
+// decodeContent determines the encoding of the content from the configured encoding, its
+// declaration, the charset detector, or auto-detection, as enabled by the options, and returns
+// a reader that yields the content transcoded to UTF-8 along with the encoding used. Content
+// whose encoding is UTF-8 or cannot be determined is returned undecoded and validated as UTF-8.
+func decodeContent(reader io.Reader, o Options) (io.Reader, detection, error) {
+	if !needsDecoding(o) {
+		return reader, detection{encoding: UTF8}, nil
+	}
+	if o.Encoding != Unknown && o.Encoding != AutoDetect {
+		enc, dec := lookupEncoding(string(o.Encoding))
+		if enc == Unknown {
+			return nil, detection{}, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, o.Encoding)
+		}
+		return decodeWith(reader, dec), detection{enc, 1}, nil
+	}
+
+	sample, full, err := sniff(reader)
+	if err != nil {
+		return nil, detection{}, err
+	}
+
+	if o.RespectDeclaredEncoding {
+		if enc, dec := lookupEncoding(declaredEncoding(sample)); enc != Unknown {
+			return decodeWith(full, dec), detection{enc, 1}, nil
+		}
+	}
+	if o.CharsetDetector != nil {
+		name, confidence := o.CharsetDetector(sample)
+		if enc, dec := lookupEncoding(name); enc != Unknown {
+			return decodeWith(full, dec), detection{enc, confidence}, nil
+		}
+	}
+	if o.Encoding == AutoDetect {
+		d := autoDetect(sample, len(sample) == sniffSize)
+		_, dec := lookupEncoding(string(d.encoding))
+		return decodeWith(full, dec), d, nil
+	}
+	return full, detection{encoding: UTF8}, nil
+}
+
+// ToUTF8 reads the content of the reader, auto-detecting its encoding as with
+// WithEncoding(AutoDetect), and returns it converted to UTF-8, for migrating files flagged by
+// Result.NeedsReencoding. Content that is already UTF-8 is returned unchanged. A byte order mark
+// is converted along with the rest of the content.
+func ToUTF8(reader io.Reader) ([]byte, error) {
+	decoded, _, err := decodeContent(reader, buildOptions([]Option{WithEncoding(AutoDetect)}))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(decoded)
+}
+
+// ErrNotPlaintext is returned by UTF8Reader when the content is not plaintext.
+var ErrNotPlaintext = errors.New("content is not plaintext")
+
+// UTF8Reader classifies the content of the reader and, if it is plaintext, returns a reader that
+// yields the whole content converted to UTF-8, for pipelines that need both a verdict and a text
+// stream. The encoding is auto-detected as with WithEncoding(AutoDetect), unless the options
+// choose one. Content that is not plaintext fails with ErrNotPlaintext.
+//
+// The bytes consumed by the classification are buffered in memory and replayed, as ReaderTee
+// does; use WithMaxTeeBuffer to bound them, in which case ErrTeeBufferExceeded is returned if
+// classification needs more.
+func UTF8Reader(r io.Reader, opts ...Option) (io.Reader, error) {
+	o := buildOptions(opts)
+	if !needsDecoding(o) {
+		o.Encoding = AutoDetect
+	}
+
+	rr := &recordingReader{r: r, max: o.MaxTeeBuffer}
+	res, err := check(rr, nil, o)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsPlaintext {
+		return nil, ErrNotPlaintext
+	}
+	decoded, _, err := decodeContent(io.MultiReader(&rr.buf, r), o)
+	return decoded, err
+}
+
+// decodeWith wraps the reader in the decoder, if any, so that it yields UTF-8.
+func decodeWith(reader io.Reader, dec encoding.Encoding) io.Reader {
+	if dec == nil {
+		return reader
+	}
+	return transform.NewReader(reader, dec.NewDecoder())
+}
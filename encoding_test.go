@@ -0,0 +1,414 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWithCharsetDetector(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		detected         string
+		expected         bool
+		expectedEncoding Encoding
+	}{
+		{"Latin-1 text", "caf\xe9 cr\xe8me\n", "ISO-8859-1", true, "ISO-8859-1"},
+		{"Latin-1 alias", "caf\xe9\n", "latin1", true, "ISO-8859-1"},
+		{"Latin-1 with control character", "caf\xe9\x07", "ISO-8859-1", false, "ISO-8859-1"},
+		{"UTF-8 text", "café\n", "UTF-8", true, UTF8},
+		{"unrecognized encoding", "caf\xe9\n", "not-a-charset", false, UTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sampled string
+			detector := func(sample []byte) (string, float64) {
+				sampled = string(sample)
+				return tt.detected, 0.9
+			}
+
+			res, err := Check(strings.NewReader(tt.content), WithCharsetDetector(detector))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if sampled != tt.content {
+				t.Errorf("detector sample = %q, want %q", sampled, tt.content)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.Encoding != tt.expectedEncoding {
+				t.Errorf("Check().Encoding = %q, want %q", res.Encoding, tt.expectedEncoding)
+			}
+		})
+	}
+}
+
+func TestWithRespectDeclaredEncoding(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		expected         bool
+		expectedEncoding Encoding
+	}{
+		{"XML prolog ISO-8859-1", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<name>caf\xe9</name>\n", true, "ISO-8859-1"},
+		{"XML prolog single quotes", "<?xml version='1.0' encoding='latin1'?>\n<name>caf\xe9</name>\n", true, "ISO-8859-1"},
+		{"HTML meta charset", "<!DOCTYPE html>\n<html><head><meta charset=\"windows-1252\"></head><body>caf\xe9</body></html>\n", true, "windows-1252"},
+		{"HTML http-equiv", "<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=ISO-8859-1\"></head><body>caf\xe9</body></html>\n", true, "ISO-8859-1"},
+		{"no declaration", "<name>caf\xe9</name>\n", false, UTF8},
+		{"unknown declaration", "<?xml version=\"1.0\" encoding=\"bogus\"?>\n<name>caf\xe9</name>\n", false, UTF8},
+		{"declared UTF-8", "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<name>café</name>\n", true, UTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithRespectDeclaredEncoding())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.Encoding != tt.expectedEncoding {
+				t.Errorf("Check().Encoding = %q, want %q", res.Encoding, tt.expectedEncoding)
+			}
+		})
+	}
+}
+
+func TestWithRespectDeclaredEncodingFallsBackToDetector(t *testing.T) {
+	detector := func([]byte) (string, float64) { return "ISO-8859-1", 0.5 }
+
+	res, err := Check(strings.NewReader("<name>caf\xe9</name>\n"), WithRespectDeclaredEncoding(), WithCharsetDetector(detector))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.IsPlaintext || res.Encoding != "ISO-8859-1" {
+		t.Errorf("Check() = %+v, want plaintext decoded from ISO-8859-1", res)
+	}
+}
+
+func TestWithEncodingEBCDIC(t *testing.T) {
+	// "HELLO" followed by an EBCDIC line feed.
+	hello := "\xc8\xc5\xd3\xd3\xd6\x25"
+	// EBCDIC 0x2F decodes to BEL.
+	bell := "\xc8\xc5\xd3\xd3\xd6\x2f"
+
+	tests := []struct {
+		name     string
+		content  string
+		opt      Option
+		expected bool
+	}{
+		{"HELLO with WithEncoding", hello, WithEncoding(EBCDIC), true},
+		{"HELLO with WithCodePage", hello, WithCodePage(37), true},
+		{"control character after decoding", bell, WithEncoding(EBCDIC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), tt.opt)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.Encoding != EBCDIC {
+				t.Errorf("Check().Encoding = %q, want %q", res.Encoding, EBCDIC)
+			}
+		})
+	}
+
+	// The raw bytes are not valid UTF-8.
+	if res, _ := Check(strings.NewReader(hello)); res.IsPlaintext {
+		t.Errorf("Check() without encoding = %v, want false", res.IsPlaintext)
+	}
+}
+
+func TestWithEncodingUnsupported(t *testing.T) {
+	_, err := Check(strings.NewReader("hello"), WithEncoding("not-a-charset"))
+	if !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("Check() error = %v, want %v", err, ErrUnsupportedEncoding)
+	}
+
+	if got := codePageEncoding(1252); got != "windows-1252" {
+		t.Errorf("codePageEncoding(1252) = %q, want %q", got, "windows-1252")
+	}
+}
+
+func TestAutoDetectConfidence(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		expected         bool
+		expectedEncoding Encoding
+		minConfidence    float64
+		maxConfidence    float64
+	}{
+		{"UTF-8 BOM", "\xef\xbb\xbfhello\n", true, UTF8, 1, 1},
+		{"UTF-16LE BOM", "\xff\xfeh\x00i\x00\n\x00", true, UTF16LE, 1, 1},
+		{"UTF-16BE BOM", "\xfe\xff\x00h\x00i\x00\n", true, UTF16BE, 1, 1},
+		{"UTF-32LE BOM", "\xff\xfe\x00\x00h\x00\x00\x00i\x00\x00\x00", true, UTF32LE, 1, 1},
+		{"UTF-32BE BOM", "\x00\x00\xfe\xff\x00\x00\x00h\x00\x00\x00i", true, UTF32BE, 1, 1},
+		{"UTF-16LE without BOM", "h\x00e\x00l\x00l\x00o\x00\n\x00", true, UTF16LE, 0.5, 0.99},
+		{"UTF-16BE without BOM", "\x00h\x00e\x00l\x00l\x00o\x00\n", true, UTF16BE, 0.5, 0.99},
+		{"binary integers with NULs", "\x01\x00\x00\x00\x02\x00\x00\x00", false, UTF8, 0.8, 0.99},
+		{"valid UTF-8", "café\n", true, UTF8, 0.8, 0.99},
+		{"Latin-1 fallback", "caf\xe9\n", true, Latin1, 0.01, 0.5},
+		{"binary decoded as Latin-1", "\x00\x01\xff\xfe\xfd", false, Latin1, 0.01, 0.5},
+		{"binary with C1 control bytes", "\x89PNG\xff\xd8\xff\xe0\x90\x91", false, UTF8, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithEncoding(AutoDetect))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.Encoding != tt.expectedEncoding {
+				t.Errorf("Check().Encoding = %q, want %q", res.Encoding, tt.expectedEncoding)
+			}
+			if res.Confidence < tt.minConfidence || res.Confidence > tt.maxConfidence {
+				t.Errorf("Check().Confidence = %v, want between %v and %v", res.Confidence, tt.minConfidence, tt.maxConfidence)
+			}
+		})
+	}
+
+	// Without detection no confidence is reported.
+	if res, _ := Check(strings.NewReader("hello")); res.Confidence != 0 {
+		t.Errorf("Check().Confidence = %v, want 0", res.Confidence)
+	}
+}
+
+func TestAutoDetectUTF16FileWithoutBOM(t *testing.T) {
+	// ASCII text encoded as UTF-16LE is half NUL bytes.
+	var content []byte
+	for _, c := range []byte("Hello, World!\nSecond line.\n") {
+		content = append(content, c, 0x00)
+	}
+	path := filepath.Join(t.TempDir(), "utf16.txt")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if res, _ := CheckFile(path); res.IsPlaintext {
+		t.Errorf("CheckFile() without detection = %v, want false", res.IsPlaintext)
+	}
+
+	res, err := CheckFile(path, WithEncoding(AutoDetect), WithNULFastReject())
+	if err != nil {
+		t.Fatalf("CheckFile() error: %v", err)
+	}
+	if !res.IsPlaintext || res.Encoding != UTF16LE {
+		t.Errorf("CheckFile() = %+v, want plaintext decoded from %s", res, UTF16LE)
+	}
+}
+
+func TestNeedsReencoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+		utf8     string
+	}{
+		{"Latin-1", "caf\xe9 cr\xe8me\n", true, "café crème\n"},
+		{"UTF-8", "café crème\n", false, "café crème\n"},
+		{"ASCII", "plain\n", false, "plain\n"},
+		{"UTF-16LE", "\xff\xfeh\x00i\x00", true, "\uFEFFhi"},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("file%d.txt", i))
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			res, err := CheckFile(path, WithEncoding(AutoDetect))
+			if err != nil {
+				t.Fatalf("CheckFile() error: %v", err)
+			}
+			if !res.IsPlaintext || res.NeedsReencoding != tt.expected {
+				t.Errorf("CheckFile() = %+v, want plaintext with NeedsReencoding %v", res, tt.expected)
+			}
+
+			converted, err := ToUTF8(strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("ToUTF8() error: %v", err)
+			}
+			if string(converted) != tt.utf8 {
+				t.Errorf("ToUTF8() = %q, want %q", converted, tt.utf8)
+			}
+		})
+	}
+
+	// Decoding ASCII changes nothing in an encoding that keeps ASCII as is.
+	declared := []struct {
+		name     string
+		content  string
+		opts     []Option
+		expected bool
+	}{
+		{"ASCII under Latin-1", "cafe\n", []Option{WithEncoding(Latin1)}, false},
+		{"accent under Latin-1", "caf\xe9\n", []Option{WithEncoding(Latin1)}, true},
+		{"declared Latin-1 with only ASCII", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<a>cafe</a>\n", []Option{WithRespectDeclaredEncoding()}, false},
+		{"ASCII letters under EBCDIC", "\x83\x81\x86\x85", []Option{WithEncoding(EBCDIC)}, true},
+	}
+	for _, tt := range declared {
+		res, err := Check(strings.NewReader(tt.content), tt.opts...)
+		if err != nil || !res.IsPlaintext || res.NeedsReencoding != tt.expected {
+			t.Errorf("Check() of %s = %+v, %v, want plaintext with NeedsReencoding %v", tt.name, res, err, tt.expected)
+		}
+	}
+
+	// Binary content does not need re-encoding, as it is not text.
+	if res, _ := Check(strings.NewReader("\x00\x01\xff"), WithEncoding(AutoDetect)); res.NeedsReencoding {
+		t.Errorf("Check().NeedsReencoding = %v, want false", res.NeedsReencoding)
+	}
+}
+
+func TestFileEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Encoding
+	}{
+		{"UTF-8 BOM", "\xef\xbb\xbfhello\n", UTF8},
+		{"UTF-16LE BOM", "\xff\xfeh\x00i\x00", UTF16LE},
+		{"UTF-16BE BOM", "\xfe\xff\x00h\x00i", UTF16BE},
+		{"UTF-32LE BOM", "\xff\xfe\x00\x00h\x00\x00\x00", UTF32LE},
+		{"UTF-32BE BOM", "\x00\x00\xfe\xff\x00\x00\x00h", UTF32BE},
+		{"BOM only", "\xef\xbb\xbf", UTF8},
+		{"no BOM", "hello\n", Unknown},
+		{"binary", "\x00\x01\x02\x03\x04", Unknown},
+		{"short", "h", Unknown},
+		{"empty", "", Unknown},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("file%d.txt", i))
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			enc, err := FileEncoding(path)
+			if err != nil {
+				t.Errorf("FileEncoding() error: %v", err)
+			}
+			if enc != tt.expected {
+				t.Errorf("FileEncoding() = %q, want %q", enc, tt.expected)
+			}
+		})
+	}
+
+	if _, err := FileEncoding(filepath.Join(dir, "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("FileEncoding() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestUTF16Endianness(t *testing.T) {
+	// utf16 encodes ASCII text as UTF-16 in the given byte order, without a byte order mark.
+	utf16 := func(text string, little bool) string {
+		var b []byte
+		for _, c := range []byte(text) {
+			if little {
+				b = append(b, c, 0x00)
+			} else {
+				b = append(b, 0x00, c)
+			}
+		}
+		return string(b)
+	}
+	ascii := "ASCII-heavy text, where every high byte is NUL.\n"
+
+	tests := []struct {
+		name          string
+		content       string
+		expected      Endianness
+		minConfidence float64
+		maxConfidence float64
+	}{
+		{"ASCII UTF-16LE", utf16(ascii, true), LittleEndian, 0.95, 0.99},
+		{"ASCII UTF-16BE", utf16(ascii, false), BigEndian, 0.95, 0.99},
+		// Half the characters are Cyrillic, whose high byte is 0x04 rather than NUL.
+		{"mixed UTF-16LE", "h\x00\x38\x04i\x00\x38\x04h\x00\x38\x04i\x00\x38\x04", LittleEndian, 0.6, 0.8},
+		{"UTF-16BE BOM", "\xfe\xff\x00h\x00i", BigEndian, 1, 1},
+		{"UTF-32LE BOM", "\xff\xfe\x00\x00h\x00\x00\x00", LittleEndian, 1, 1},
+		{"UTF-8", "hello\n", UnknownEndianness, 0.8, 0.99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithEncoding(AutoDetect))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if !res.IsPlaintext || res.Endianness != tt.expected {
+				t.Errorf("Check() = %+v, want plaintext with endianness %v", res, tt.expected)
+			}
+			if res.Confidence < tt.minConfidence || res.Confidence > tt.maxConfidence {
+				t.Errorf("Check().Confidence = %v, want between %v and %v", res.Confidence, tt.minConfidence, tt.maxConfidence)
+			}
+		})
+	}
+
+	if got := LittleEndian.String(); got != "LittleEndian" {
+		t.Errorf("LittleEndian.String() = %q, want %q", got, "LittleEndian")
+	}
+}
+
+func TestUTF8Reader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    []Option
+		utf8    string
+	}{
+		{"Latin-1", "caf\xe9 cr\xe8me br\xfbl\xe9e\n", nil, "café crème brûlée\n"},
+		{"UTF-8", "café\n", nil, "café\n"},
+		{"UTF-16LE with BOM", "\xff\xfeh\x00i\x00\n\x00", nil, "\uFEFFhi\n"},
+		{"Windows-1252", "\x93quoted\x94\n", []Option{WithCodePage(1252)}, "“quoted”\n"},
+		{"Latin-1 beyond the buffered sample", "\xe9" + strings.Repeat("a", 64*1024), nil, "é" + strings.Repeat("a", 64*1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := UTF8Reader(strings.NewReader(tt.content), tt.opts...)
+			if err != nil {
+				t.Fatalf("UTF8Reader() error: %v", err)
+			}
+			converted, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if string(converted) != tt.utf8 {
+				t.Errorf("UTF8Reader() yields %q, want %q", converted, tt.utf8)
+			}
+			if !utf8.Valid(converted) {
+				t.Errorf("UTF8Reader() yields invalid UTF-8 %q", converted)
+			}
+		})
+	}
+
+	if _, err := UTF8Reader(bytes.NewReader([]byte{0x89, 'P', 'N', 'G', 0x00, 0x01})); !errors.Is(err, ErrNotPlaintext) {
+		t.Errorf("UTF8Reader() error = %v, want %v", err, ErrNotPlaintext)
+	}
+	if _, err := UTF8Reader(strings.NewReader(strings.Repeat("a", 64*1024)), WithMaxTeeBuffer(1024)); !errors.Is(err, ErrTeeBufferExceeded) {
+		t.Errorf("UTF8Reader() error = %v, want %v", err, ErrTeeBufferExceeded)
+	}
+}
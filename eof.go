@@ -0,0 +1,29 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrDataAfterEOF is returned in strict EOF mode when a reader returns data after it has returned io.EOF.
+var ErrDataAfterEOF = errors.New("reader returned data after io.EOF")
+
+// strictEOFReader confirms that the wrapped reader stays at EOF once it has reported io.EOF.
+type strictEOFReader struct {
+	r io.Reader
+}
+
+// Read implements io.Reader. When the wrapped reader reports io.EOF, it is read once more
+// and any data returned is reported as ErrDataAfterEOF.
+func (s *strictEOFReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != io.EOF {
+		return n, err
+	}
+
+	var probe [1]byte
+	if extra, _ := s.r.Read(probe[:]); extra > 0 {
+		return n, ErrDataAfterEOF
+	}
+	return n, io.EOF
+}
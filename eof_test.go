@@ -0,0 +1,53 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// resumingReader returns io.EOF after each of its chunks, including ones that are followed by more data.
+type resumingReader struct {
+	chunks []string
+}
+
+func (r *resumingReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, io.EOF
+}
+
+func TestWithStrictEOF(t *testing.T) {
+	// The default lenient mode stops at the first io.EOF.
+	res, err := Check(&resumingReader{chunks: []string{"hello", "more"}})
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.IsPlaintext {
+		t.Errorf("Check().IsPlaintext = %v, want true", res.IsPlaintext)
+	}
+
+	_, err = Check(&resumingReader{chunks: []string{"hello", "more"}}, WithStrictEOF())
+	if !errors.Is(err, ErrDataAfterEOF) {
+		t.Errorf("Check(WithStrictEOF) error = %v, want %v", err, ErrDataAfterEOF)
+	}
+
+	// Best effort does not hide the broken reader.
+	_, err = Check(&resumingReader{chunks: []string{"hello", "more"}}, WithStrictEOF(), WithBestEffortOnError())
+	if !errors.Is(err, ErrDataAfterEOF) {
+		t.Errorf("Check(WithStrictEOF, WithBestEffortOnError) error = %v, want %v", err, ErrDataAfterEOF)
+	}
+
+	// A well-behaved reader passes.
+	res, err = Check(strings.NewReader("hello"), WithStrictEOF())
+	if err != nil {
+		t.Errorf("Check(WithStrictEOF) error: %v", err)
+	}
+	if !res.IsPlaintext {
+		t.Errorf("Check(WithStrictEOF).IsPlaintext = %v, want true", res.IsPlaintext)
+	}
+}
@@ -0,0 +1,39 @@
+package isplaintextfile
+
+import (
+	"io/fs"
+)
+
+// readFileThreshold is the largest file that FileFS reads in one call on file systems that implement fs.ReadFileFS.
+const readFileThreshold = 64 * 1024
+
+// FileFS opens the named file in the file system and checks if its entire content is plaintext.
+// When the file system implements fs.ReadFileFS, as embed.FS does, small files are read in a
+// single ReadFile call instead of being streamed.
+func FileFS(fsys fs.FS, name string) (bool, error) {
+	o := buildOptions(nil)
+
+	if rfs, ok := fsys.(fs.ReadFileFS); ok {
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			return false, err
+		}
+		if info.Size() <= readFileThreshold {
+			data, err := rfs.ReadFile(name)
+			if err != nil {
+				return false, err
+			}
+			res, err := checkBytes(data, o)
+			return res.IsPlaintext, err
+		}
+	}
+
+	file, err := fsys.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	res, err := check(file, nil, o)
+	return res.IsPlaintext, err
+}
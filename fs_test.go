@@ -0,0 +1,82 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+// countingFS is a fs.ReadFileFS, like embed.FS, that counts ReadFile calls.
+type countingFS struct {
+	fstest.MapFS
+	readFiles int
+}
+
+func (c *countingFS) ReadFile(name string) ([]byte, error) {
+	c.readFiles++
+	return c.MapFS.ReadFile(name)
+}
+
+// openOnlyFS hides every method except Open.
+type openOnlyFS struct {
+	fsys fs.FS
+}
+
+func (o openOnlyFS) Open(name string) (fs.File, error) {
+	return o.fsys.Open(name)
+}
+
+func TestFileFS(t *testing.T) {
+	files := fstest.MapFS{
+		"small.txt":  {Data: []byte("Hello, World!\n")},
+		"small.bin":  {Data: []byte{0x00, 0x01, 0x02, 0x03}},
+		"large.txt":  {Data: bytes.Repeat([]byte("Large plain text content\n"), 10000)},
+		"large.bin":  {Data: append(bytes.Repeat([]byte("Large plain text content\n"), 10000), 0x00)},
+		"emoji.txt":  {Data: []byte("Hello 👋 World! 🌍\n")},
+		"nested/a.c": {Data: []byte("int main(void) { return 0; }\n")},
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		expected  bool
+		readFiles int
+	}{
+		{"small text", "small.txt", true, 1},
+		{"small binary", "small.bin", false, 1},
+		{"emoji", "emoji.txt", true, 1},
+		{"nested", "nested/a.c", true, 1},
+		{"large text", "large.txt", true, 0},
+		{"large binary", "large.bin", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := &countingFS{MapFS: files}
+			res, err := FileFS(fsys, tt.file)
+			if err != nil {
+				t.Errorf("FileFS() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("FileFS() = %v, want %v", res, tt.expected)
+			}
+			if fsys.readFiles != tt.readFiles {
+				t.Errorf("FileFS() made %d ReadFile calls, want %d", fsys.readFiles, tt.readFiles)
+			}
+
+			// File systems without ReadFile are streamed.
+			res, err = FileFS(openOnlyFS{files}, tt.file)
+			if err != nil {
+				t.Errorf("FileFS() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("FileFS() without ReadFile = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	if _, err := FileFS(&countingFS{MapFS: files}, "missing.txt"); err == nil {
+		t.Errorf("FileFS() for missing file error = nil, want error")
+	}
+}
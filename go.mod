@@ -1,3 +1,9 @@
 module github.com/UnitVectorY-Labs/isplaintextfile
 
 go 1.26.0 // GOVERSION
+
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/ulikunitz/xz v0.5.12
+	golang.org/x/text v0.42.0
+)
@@ -0,0 +1,56 @@
+package isplaintextfile
+
+import (
+	"io"
+	"time"
+)
+
+// idleReader ends the content when the wrapped reader delivers nothing for the idle timeout, for
+// streams that stop sending without ever returning io.EOF. Reads run in a separate goroutine so
+// they can be abandoned; after a timeout the abandoned read is left to finish on its own.
+type idleReader struct {
+	r       io.Reader
+	timeout time.Duration
+
+	// buf receives each read in the background, and results its outcome.
+	buf     []byte
+	results chan idleRead
+
+	// idled reports that the timeout passed, after which every read reports io.EOF.
+	idled bool
+}
+
+// idleRead is the outcome of a background read.
+type idleRead struct {
+	n   int
+	err error
+}
+
+func newIdleReader(r io.Reader, timeout time.Duration) *idleReader {
+	return &idleReader{r: r, timeout: timeout, results: make(chan idleRead, 1)}
+}
+
+// Read implements io.Reader. It reports io.EOF once a read has waited longer than the timeout.
+func (ir *idleReader) Read(p []byte) (int, error) {
+	if ir.idled {
+		return 0, io.EOF
+	}
+	if cap(ir.buf) < len(p) {
+		ir.buf = make([]byte, len(p))
+	}
+	buf := ir.buf[:len(p)]
+	go func() {
+		n, err := ir.r.Read(buf)
+		ir.results <- idleRead{n, err}
+	}()
+
+	timer := time.NewTimer(ir.timeout)
+	defer timer.Stop()
+	select {
+	case res := <-ir.results:
+		return copy(p, buf[:res.n]), res.err
+	case <-timer.C:
+		ir.idled = true
+		return 0, io.EOF
+	}
+}
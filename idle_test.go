@@ -0,0 +1,66 @@
+package isplaintextfile
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stallingReader returns its content and then blocks until released, without returning io.EOF.
+type stallingReader struct {
+	r       io.Reader
+	release chan struct{}
+}
+
+func (s *stallingReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err == io.EOF {
+		<-s.release
+	}
+	return n, err
+}
+
+func TestWithIdleTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"text", "Hello, World!\n", true},
+		{"binary", "\x00\x01\x02", false},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &stallingReader{r: strings.NewReader(tt.content), release: make(chan struct{})}
+			defer close(reader.release)
+
+			res, err := Check(reader, WithIdleTimeout(20*time.Millisecond))
+			if err != nil {
+				t.Fatalf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if tt.expected && !res.Incomplete {
+				t.Error("Check().Incomplete = false, want true")
+			}
+		})
+	}
+
+	// A reader that reaches EOF is complete.
+	res, err := Check(strings.NewReader("Hello\n"), WithIdleTimeout(time.Second))
+	if err != nil || !res.IsPlaintext || res.Incomplete {
+		t.Errorf("Check() = %+v, %v, want complete plaintext", res, err)
+	}
+
+	// The stream ends after a rune split across the stall without rejecting the content.
+	reader := &stallingReader{r: strings.NewReader("caf\xc3"), release: make(chan struct{})}
+	defer close(reader.release)
+	res, err = Check(reader, WithIdleTimeout(20*time.Millisecond))
+	if err != nil || !res.IsPlaintext || !res.Incomplete {
+		t.Errorf("Check() = %+v, %v, want incomplete plaintext", res, err)
+	}
+}
@@ -0,0 +1,112 @@
+package isplaintextfile
+
+// IndentStyle is how the lines of content are indented.
+type IndentStyle int
+
+const (
+	// None means no line is indented.
+	None IndentStyle = iota
+
+	// Tabs means indented lines are indented with tabs only.
+	Tabs
+
+	// Spaces means indented lines are indented with spaces only.
+	Spaces
+
+	// Mixed means some lines are indented with tabs and others with spaces, or with both.
+	Mixed
+)
+
+// String returns the name of the indentation style.
+func (s IndentStyle) String() string {
+	switch s {
+	case None:
+		return "None"
+	case Tabs:
+		return "Tabs"
+	case Spaces:
+		return "Spaces"
+	case Mixed:
+		return "Mixed"
+	}
+	return "Unknown"
+}
+
+// maxIndentWidth is the widest indentation step considered when inferring the width of spaces.
+const maxIndentWidth = 8
+
+// indentStats accumulates the leading whitespace of each line.
+type indentStats struct {
+	// done reports that the indentation of the current line has ended; tabs and spaces count
+	// the leading whitespace so far.
+	done   bool
+	tabs   int
+	spaces int
+
+	tabLines, spaceLines, mixedLines int
+
+	// previous is the number of leading spaces of the last line indented with spaces only, or
+	// not indented at all. steps counts the changes between consecutive such lines by size.
+	previous int
+	steps    [maxIndentWidth + 1]int
+}
+
+// next updates the statistics for the next rune. lineBreak reports that the rune ends a line.
+func (st *indentStats) next(r rune, lineBreak bool) {
+	switch {
+	case lineBreak:
+		st.done, st.tabs, st.spaces = false, 0, 0
+	case st.done:
+	case r == '\t':
+		st.tabs++
+	case r == ' ':
+		st.spaces++
+	default:
+		// The first other rune ends the indentation; lines of whitespace only are ignored.
+		st.done = true
+		st.endIndent()
+	}
+}
+
+// endIndent records the indentation of a line with content.
+func (st *indentStats) endIndent() {
+	switch {
+	case st.tabs > 0 && st.spaces > 0:
+		st.mixedLines++
+		return
+	case st.tabs > 0:
+		st.tabLines++
+		return
+	case st.spaces > 0:
+		st.spaceLines++
+	}
+
+	step := st.spaces - st.previous
+	if step < 0 {
+		step = -step
+	}
+	if step > 0 && step <= maxIndentWidth {
+		st.steps[step]++
+	}
+	st.previous = st.spaces
+}
+
+// style returns the indentation style and, for spaces, the most common indentation step.
+func (st *indentStats) style() (IndentStyle, int) {
+	switch {
+	case st.mixedLines > 0 || st.tabLines > 0 && st.spaceLines > 0:
+		return Mixed, 0
+	case st.tabLines > 0:
+		return Tabs, 0
+	case st.spaceLines == 0:
+		return None, 0
+	}
+
+	width := 0
+	for step := 1; step <= maxIndentWidth; step++ {
+		if st.steps[step] > st.steps[width] {
+			width = step
+		}
+	}
+	return Spaces, width
+}
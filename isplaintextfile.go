@@ -1,105 +1,697 @@
 package isplaintextfile
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 	"unicode/utf8"
 )
 
+const (
+	// defaultBufferSize is the size of the read buffer allocated when the caller does not supply one.
+	defaultBufferSize = 32 * 1024
+
+	// minScratchSize is the smallest caller-supplied scratch buffer that will be used for reads.
+	minScratchSize = 512
+)
+
 // isBufferPlaintext examines a slice of bytes and returns whether it appears to be valid plaintext.
+// Content is never plaintext under contradictory default options.
 func isBufferPlaintext(buffer []byte) bool {
-	if !utf8.Valid(buffer) {
-		return false
+	o := buildOptions(nil)
+	if isAllowedASCII(buffer, o) {
+		return true
 	}
+	res, _ := checkBytes(buffer, o)
+	return res.IsPlaintext
+}
 
-	pos := 0
-	for pos < len(buffer) {
-		r, size := utf8.DecodeRune(buffer[pos:])
-
-		// Check for control characters (except whitespace)
-		if r < 32 && r != '\n' && r != '\r' && r != '\t' {
+// isAllowedASCII reports whether the options are the package's original ones and data is
+// entirely ASCII allowed by them, which is enough for it to be plaintext. It lets the in-memory
+// functions that only report the verdict skip the scanner for short text.
+func isAllowedASCII[T string | []byte](data T, o Options) bool {
+	if !o.unconfigured {
+		return false
+	}
+	for i := 0; i < len(data); i++ {
+		if data[i] >= utf8.RuneSelf || !o.allowedASCII[data[i]] {
 			return false
 		}
-
-		pos += size
 	}
 	return true
 }
 
 // isPlaintextFromReader reads from the given reader and checks if the content is valid plaintext.
-func isPlaintextFromReader(reader io.Reader) (bool, error) {
-	buffer := make([]byte, 0, 32*1024)
-	tempBuf := make([]byte, 1024)
+func isPlaintextFromReader(reader io.Reader, buffer []byte) (bool, error) {
+	res, err := check(reader, buffer, buildOptions(nil))
+	return res.IsPlaintext, err
+}
+
+// checkBytes classifies in-memory content according to the options. The only error is an
+// *OptionError for contradictory options.
+func checkBytes(data []byte, o Options) (Result, error) {
+	if needsDecoding(o) {
+		// Decoding goes through the reader path; an in-memory reader cannot fail.
+		return check(bytes.NewReader(data), nil, o)
+	}
+	if err := o.Validate(); err != nil {
+		return Result{}, err
+	}
+	s := scanner{opts: o}
+	s.feed(data)
+	return s.result(), nil
+}
 
+// scanReader streams the reader through the scanner using buffer, which is allocated if nil.
+// Reading stops as soon as the scanner rejects the content, or accepts it early as configured.
+func scanReader(reader io.Reader, buffer []byte, s *scanner) (Result, error) {
+	if buffer == nil {
+		buffer = make([]byte, defaultBufferSize)
+	}
+
+	var scanned int64
 	for {
-		n, err := reader.Read(tempBuf)
-		if n > 0 {
-			buffer = append(buffer, tempBuf[:n]...)
+		n, err := reader.Read(buffer)
+		scanned += int64(n)
+		if s.opts.Progress != nil && n > 0 {
+			s.opts.Progress(scanned)
+		}
+		if n > 0 && !s.feed(buffer[:n]) {
+			return s.result(), nil
 		}
 		if err == io.EOF {
+			if s.idle != nil && s.idle.idled {
+				return s.incompleteResult(), nil
+			}
+			if s.preview != nil && s.preview.reachedLimit() {
+				// A rune cut by the preview limit is completed past it.
+				s.carryLen = 0
+			}
 			break
 		}
 		if err != nil {
-			return false, err
+			// Best effort needs some content to judge, and never overrides a limit.
+			if s.opts.BestEffortOnError && scanned > 0 && !isLimitError(err) {
+				return s.incompleteResult(), nil
+			}
+			return Result{}, err
+		}
+		if s.opts.SingleReadDecision {
+			return s.incompleteResult(), nil
+		}
+		if s.opts.StopCondition != nil && s.opts.StopCondition(ScanStats{BytesRead: scanned, RunesRead: s.runes, Lines: s.lines}) {
+			return s.incompleteResult(), nil
+		}
+		if s.opts.StopWhenTextBytes > 0 && scanned >= s.opts.StopWhenTextBytes && s.clean() {
+			res := s.incompleteResult()
+			res.EarlyAccepted = true
+			return res, nil
+		}
+		if !s.deadline.IsZero() && time.Now().After(s.deadline) {
+			res := s.incompleteResult()
+			res.TimedOut = true
+			return res, nil
 		}
 	}
 
-	if len(buffer) == 0 {
-		return true, nil
+	return s.result(), nil
+}
+
+// isLimitError reports whether err enforces a limit or check set by the options, which must be
+// returned even under WithBestEffortOnError.
+func isLimitError(err error) bool {
+	return errors.Is(err, ErrTeeBufferExceeded) || errors.Is(err, ErrDecompressionLimitExceeded) ||
+		errors.Is(err, ErrDataAfterEOF)
+}
+
+// lenReader is implemented by in-memory readers, such as bytes.Reader and strings.Reader,
+// that know how many unread bytes remain.
+type lenReader interface {
+	Len() int
+}
+
+// newReadBuffer allocates a read buffer for the reader. When the reader reports its remaining
+// length, the buffer is sized to the content (capped by limit, if positive) instead of the default.
+func newReadBuffer(reader io.Reader, limit int64) []byte {
+	size := int64(defaultBufferSize)
+	if lr, ok := reader.(lenReader); ok {
+		size = min(size, int64(lr.Len()))
+	}
+	if limit > 0 {
+		size = min(size, limit)
+	}
+	return make([]byte, max(size, 1))
+}
+
+// NoLimit as the maxKB of a preview function, such as FilePreview or ReaderPreview, reads the
+// content to the end.
+const NoLimit = -1
+
+// ErrInvalidLength is returned when a length or limit argument is out of range.
+var ErrInvalidLength = errors.New("invalid length")
+
+// ErrInvalidOffset is returned when an offset argument is out of range.
+var ErrInvalidOffset = errors.New("invalid offset")
+
+// validatePreviewKB returns an error unless the kilobyte limit of a preview is positive or NoLimit.
+func validatePreviewKB(maxKB int) error {
+	if maxKB <= 0 && maxKB != NoLimit {
+		return fmt.Errorf("%w: maxKB must be greater than 0 or NoLimit", ErrInvalidLength)
+	}
+	return nil
+}
+
+// limitPreview limits the reader to maxKB kilobytes, or leaves it unlimited for NoLimit.
+func limitPreview(reader io.Reader, maxKB int) io.Reader {
+	if maxKB == NoLimit {
+		return reader
+	}
+	return newPreviewReader(reader, previewLimit(maxKB))
+}
+
+// previewReader limits the content of a preview like io.LimitedReader. A check of a previewReader
+// does not reject a multibyte rune cut by the limit, only the runes before it.
+type previewReader struct {
+	io.LimitedReader
+}
+
+// newPreviewReader limits the reader to n bytes for a preview.
+func newPreviewReader(reader io.Reader, n int64) *previewReader {
+	return &previewReader{io.LimitedReader{R: reader, N: n}}
+}
+
+// reachedLimit reports whether the preview stopped at its limit, so the content may go on past it.
+func (p *previewReader) reachedLimit() bool {
+	return p.N <= 0
+}
+
+// previewLimit converts a limit in kilobytes to bytes.
+// The multiplication is done in int64 so large limits do not overflow on 32-bit platforms.
+func previewLimit(maxKB int) int64 {
+	return int64(maxKB) * 1024
+}
+
+// Check reads from the given reader and classifies its content according to the supplied options.
+func Check(reader io.Reader, opts ...Option) (Result, error) {
+	return check(reader, nil, buildOptions(opts))
+}
+
+// check decodes the reader as configured by the options and scans the result.
+func check(reader io.Reader, buffer []byte, o Options) (Result, error) {
+	if err := o.Validate(); err != nil {
+		return Result{}, err
+	}
+	s := &scanner{opts: o}
+	s.preview, _ = reader.(*previewReader)
+	if o.TimeBudget > 0 {
+		s.deadline = time.Now().Add(o.TimeBudget)
 	}
-	return isBufferPlaintext(buffer), nil
+	if o.StrictEOF {
+		reader = &strictEOFReader{r: reader}
+	}
+	if o.IdleTimeout > 0 {
+		s.idle = newIdleReader(reader, o.IdleTimeout)
+		reader = s.idle
+	}
+	if o.PreviewKB > 0 {
+		s.preview = newPreviewReader(reader, previewLimit(o.PreviewKB))
+		reader = s.preview
+	}
+	reader, d, err := decodeContent(reader, o)
+	if err != nil {
+		return Result{}, err
+	}
+	s.detection = d
+	return scanReader(reader, buffer, s)
+}
+
+// DetectWithLineCount reads from the given reader and classifies its content like Check, for
+// tools that show file stats. Result.Lines holds the line count, computed in the same scan as
+// the plaintext check.
+func DetectWithLineCount(reader io.Reader) (Result, error) {
+	return Check(reader)
+}
+
+// DetectWithWordCount reads from the given reader and classifies its content like Check, for
+// document tooling. Result.Words holds the number of words in plaintext, as with WithCountWords,
+// computed in the same scan as the plaintext check.
+func DetectWithWordCount(reader io.Reader) (Result, error) {
+	return Check(reader, WithCountWords())
+}
+
+// Analyze reads from the given reader and returns every piece of metadata enabled by the options,
+// computed in a single pass alongside the classification. The counts, ratios, and line statistics
+// of Result are always reported; the rest are only computed for the options that enable them:
+//
+//   - Encoding and Confidence: WithEncoding(AutoDetect), WithCharsetDetector, or
+//     WithRespectDeclaredEncoding
+//   - Snippet: WithSnippet
+//   - Script: WithDetectScript
+//   - IndentStyle and IndentWidth: WithDetectIndentation
+//   - TrailingWhitespaceLines and TrailingWhitespaceCount: WithTrackTrailingWhitespace
+//   - NotNormalized: WithRequireNFC
+//   - MaxByteWidth: WithDetectByteWidth
+//   - ContainsTemplateMarkers: WithDetectTemplateMarkers
+//   - StructuredIndentLikely: WithDetectStructuredIndent
+//   - Delimited, Delimiter, and Columns: WithDetectDelimited
+//   - Words: WithCountWords
+//   - LineEndings: WithNormalizeForMetrics
+//
+// Metadata for features that are not enabled is not computed. Analyze is the same check as Check,
+// named for callers gathering metadata rather than a verdict.
+func Analyze(reader io.Reader, opts ...Option) (Result, error) {
+	return check(reader, nil, buildOptions(opts))
 }
 
 // Bytes checks if the provided byte slice is valid plaintext.
 func Bytes(data []byte) (bool, error) {
-	// In-memory data: no IO error is expected.
-	return isBufferPlaintext(data), nil
+	if isAllowedASCII(data, buildOptions(nil)) {
+		return true, nil
+	}
+	res, err := BytesDetailed(data)
+	return res.IsPlaintext, err
+}
+
+// BytesDetailed classifies the provided byte slice like Bytes and returns the full Result.
+// Use Check with a bytes.Reader to enable metadata options.
+func BytesDetailed(data []byte) (Result, error) {
+	// In-memory data: no IO error is expected, only contradictory default options.
+	return checkBytes(data, buildOptions(nil))
+}
+
+// MustBytes reports whether the provided byte slice is valid plaintext.
+// In-memory analysis cannot fail, so unlike Bytes there is no error to check. If the default
+// options set by SetDefaultOptions contradict each other, nothing is plaintext.
+func MustBytes(data []byte) bool {
+	return isBufferPlaintext(data)
+}
+
+// MustString reports whether the provided string is valid plaintext.
+// In-memory analysis cannot fail, so there is no error to check. As with MustBytes, nothing is
+// plaintext under contradictory default options.
+func MustString(s string) bool {
+	if isAllowedASCII(s, buildOptions(nil)) {
+		return true
+	}
+	return isBufferPlaintext([]byte(s))
 }
 
 // File opens the file at the given path and checks if its entire content is plaintext.
 func File(path string) (bool, error) {
-	file, err := os.Open(path)
+	res, err := FileDetailed(path)
+	return res.IsPlaintext, err
+}
+
+// FileDetailed classifies the file at the given path like File and returns the full Result.
+// Use CheckFile to enable metadata options.
+func FileDetailed(path string) (Result, error) {
+	return checkFile(path, buildOptions(nil))
+}
+
+// CheckFile opens the file at the given path and classifies its content according to the supplied options.
+func CheckFile(path string, opts ...Option) (Result, error) {
+	return checkFile(path, buildOptions(opts))
+}
+
+// SameClassification reports whether the files at the two paths are both plaintext or both not,
+// classified with the same options, such as to verify that a migration or transformation kept a
+// text file text. The first error from classifying either file is returned.
+func SameClassification(pathA, pathB string, opts ...Option) (bool, error) {
+	o := buildOptions(opts)
+	a, err := checkFile(pathA, o)
 	if err != nil {
 		return false, err
 	}
-	defer file.Close()
+	b, err := checkFile(pathB, o)
+	if err != nil {
+		return false, err
+	}
+	return a.IsPlaintext == b.IsPlaintext, nil
+}
+
+// checkFile opens the file at the given path and classifies it, applying the file-level policies
+// in the options before any content is read.
+func checkFile(path string, o Options) (Result, error) {
+	if forced, err := matchPath(o.ForceBinary, path); err != nil {
+		return Result{}, err
+	} else if forced {
+		return Result{Reason: ForcedBinary}, nil
+	}
+	if forced, err := matchPath(o.ForceText, path); err != nil {
+		return Result{}, err
+	} else if forced {
+		return Result{IsPlaintext: true, Classification: PlainText, Reason: ForcedText}, nil
+	}
+
+	file, closeFile, err := o.openFile(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer closeFile()
+
+	if o.MaxFileSize > 0 {
+		info, err := file.Stat()
+		if err != nil {
+			return Result{}, err
+		}
+		if info.Size() > o.MaxFileSize {
+			return Result{Reason: SkippedTooLarge}, nil
+		}
+	}
 
-	return isPlaintextFromReader(file)
+	return check(file, nil, o)
+}
+
+// openFile opens the file at path once the WithMaxOpenFiles limit allows it. The returned
+// function closes the file and releases its slot; it must be called even if the file is not
+// used.
+func (o Options) openFile(path string) (*os.File, func(), error) {
+	if o.openFiles != nil {
+		o.openFiles <- struct{}{}
+	}
+	release := func() {
+		if o.openFiles != nil {
+			<-o.openFiles
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return file, func() {
+		file.Close()
+		release()
+	}, nil
+}
+
+// matchPath reports whether the file path matches one of the glob patterns. A pattern is matched
+// against as many trailing path elements as it has, so "*.js" matches the file name and
+// "vendor/*.js" the file name and its parent directory.
+func matchPath(patterns []string, filePath string) (bool, error) {
+	elems := strings.Split(filepath.ToSlash(filePath), "/")
+	for _, pattern := range patterns {
+		n := strings.Count(pattern, "/") + 1
+		if n > len(elems) {
+			continue
+		}
+		matched, err := path.Match(pattern, strings.Join(elems[len(elems)-n:], "/"))
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
 }
 
 // FilePreview opens the file at the given path, reads up to maxKB kilobytes,
-// and checks if that portion of the file is plaintext.
+// and checks if that portion of the file is plaintext. NoLimit checks the whole file.
+// The preview always examines the file from its first byte, however small the limit. A multibyte
+// rune cut by the limit is left out of the preview rather than rejected as invalid.
 func FilePreview(path string, maxKB int) (bool, error) {
+	res, err := FilePreviewDetailed(path, maxKB)
+	return res.IsPlaintext, err
+}
+
+// FilePreviewDetailed classifies up to maxKB kilobytes of the file at the given path like
+// FilePreview and returns the full Result.
+func FilePreviewDetailed(path string, maxKB int) (Result, error) {
+	if err := validatePreviewKB(maxKB); err != nil {
+		return Result{IsPlaintext: true}, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer file.Close()
+
+	// Limit the reader to maxKB*1024 bytes.
+	return check(limitPreview(file, maxKB), nil, buildOptions(nil))
+}
+
+// FileTailPreview opens the file at the given path and checks if its last maxKB kilobytes are
+// plaintext, such as the recent entries appended to a log file. A file smaller than the window is
+// checked whole, as is any file for NoLimit. When the window starts in the middle of a multibyte
+// rune, its start is moved forward to the next rune.
+func FileTailPreview(path string, maxKB int) (bool, error) {
+	if err := validatePreviewKB(maxKB); err != nil {
+		return true, err
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return false, err
 	}
 	defer file.Close()
 
-	if maxKB == 0 {
-		return true, errors.New("invalid length: maxKB must be greater than 0")
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	var start int64
+	if maxKB != NoLimit {
+		start = max(info.Size()-previewLimit(maxKB), 0)
+	}
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return false, err
 	}
 
-	// Limit the reader to maxKB*1024 bytes.
-	limitedReader := io.LimitReader(file, int64(maxKB*1024))
-	return isPlaintextFromReader(limitedReader)
+	reader := bufio.NewReader(file)
+	for i := 0; start > 0 && i < utf8.UTFMax-1; i++ {
+		b, err := reader.Peek(1)
+		if err != nil || utf8.RuneStart(b[0]) {
+			break
+		}
+		_, _ = reader.Discard(1)
+	}
+	return isPlaintextFromReader(reader, nil)
+}
+
+// MinStablePreview opens the file at the given path and reports the smallest preview, in
+// kilobytes, from which FilePreview gives the same verdict as File, for tuning preview limits.
+// For a file that is not plaintext, kb is the kilobyte holding the first byte that rejects it, so
+// any preview of at least kb kilobytes rejects it too. For a plaintext file, kb is its size
+// rounded up to whole kilobytes, as the verdict is only settled once every byte has been
+// examined. The file is read once, up to the rejecting kilobyte. An empty file reports 1.
+func MinStablePreview(path string) (kb int, verdict bool, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+
+	o := buildOptions(nil)
+	if err := o.Validate(); err != nil {
+		return 0, false, err
+	}
+	s := scanner{opts: o}
+	chunk := make([]byte, previewLimit(1))
+	for kb = 1; ; kb++ {
+		n, err := io.ReadFull(file, chunk)
+		if n > 0 && !s.feed(chunk[:n]) {
+			return kb, false, nil
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// A read that found nothing is past the last kilobyte.
+			if n == 0 && kb > 1 {
+				kb--
+			}
+			return kb, s.result().IsPlaintext, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+	}
+}
+
+// FileMiddleSample opens the file at the given path and checks if sampleKB kilobytes starting at
+// the middle of the file are plaintext. This suits formats with text headers and footers around a
+// binary body, or the reverse, which a preview of the start would misjudge. A multibyte rune cut
+// by either end of the sample is skipped at the start and completed at the end.
+func FileMiddleSample(path string, sampleKB int) (bool, error) {
+	if sampleKB <= 0 {
+		return true, fmt.Errorf("%w: sampleKB must be greater than 0", ErrInvalidLength)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	// The sample never extends past the end of the file, so the buffer is sized by whichever is
+	// smaller; the comparison is done in int64 so large limits do not overflow.
+	offset := info.Size() / 2
+	limit := int(min(previewLimit(sampleKB), info.Size()-offset))
+
+	// Read enough extra bytes to align both ends of the sample to rune boundaries.
+	buffer := make([]byte, limit+2*(utf8.UTFMax-1))
+	n, err := file.ReadAt(buffer, offset)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	sample := buffer[:n]
+
+	start := 0
+	for start < utf8.UTFMax-1 && start < len(sample) && !utf8.RuneStart(sample[start]) {
+		start++
+	}
+	end := min(start+limit, len(sample))
+	for end < len(sample) && end < start+limit+utf8.UTFMax-1 && !utf8.RuneStart(sample[end]) {
+		end++
+	}
+	res, err := checkBytes(sample[start:end], buildOptions(nil))
+	return res.IsPlaintext, err
+}
+
+// FileRange opens the file at the given path and checks if length bytes starting at offset are
+// plaintext. If length is zero or negative, the file is checked from offset to the end. This is
+// useful for formats with a known binary header in front of a text payload.
+func FileRange(path string, offset, length int64) (bool, error) {
+	res, err := checkFileRange(path, offset, length, buildOptions(nil))
+	return res.IsPlaintext, err
+}
+
+// checkFileRange classifies length bytes of the file starting at offset, or everything from
+// offset to the end of the file if length is not positive.
+func checkFileRange(path string, offset, length int64, o Options) (Result, error) {
+	if offset < 0 {
+		return Result{IsPlaintext: true}, fmt.Errorf("%w: offset must not be negative", ErrInvalidOffset)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return Result{}, err
+	}
+
+	var reader io.Reader = file
+	if length > 0 {
+		reader = io.LimitReader(file, length)
+	}
+	return check(reader, nil, o)
+}
+
+// Section checks if exactly length bytes of r starting at offset are plaintext, such as a file
+// stored at a known position inside an archive. The window is read in place through an
+// io.SectionReader, so it does not need to be copied out first.
+func Section(r io.ReaderAt, offset, length int64) (bool, error) {
+	if offset < 0 {
+		return true, fmt.Errorf("%w: offset must not be negative", ErrInvalidOffset)
+	}
+	if length < 0 {
+		return true, fmt.Errorf("%w: length must not be negative", ErrInvalidLength)
+	}
+	section := io.NewSectionReader(r, offset, length)
+	return isPlaintextFromReader(section, newReadBuffer(section, 0))
 }
 
 // Reader checks if the content provided by the io.Reader is plaintext.
 func Reader(reader io.Reader) (bool, error) {
-	return isPlaintextFromReader(reader)
+	res, err := ReaderDetailed(reader)
+	return res.IsPlaintext, err
+}
+
+// ReaderDetailed classifies the content provided by the io.Reader like Reader and returns the
+// full Result. Use Check to enable metadata options.
+func ReaderDetailed(reader io.Reader) (Result, error) {
+	return check(reader, newReadBuffer(reader, 0), buildOptions(nil))
 }
 
 // ReaderPreview checks if the content provided by the io.Reader is plaintext,
-// reading only up to maxKB kilobytes from the reader.
+// reading only up to maxKB kilobytes from the reader. NoLimit reads to the end.
+// The preview always examines the content from its first byte, however small the limit. As with
+// FilePreview, a multibyte rune cut by the limit is left out of the preview.
 func ReaderPreview(reader io.Reader, maxKB int) (bool, error) {
-	maxBytes := maxKB * 1024
+	res, err := ReaderPreviewDetailed(reader, maxKB)
+	return res.IsPlaintext, err
+}
+
+// ReaderPreviewDetailed classifies up to maxKB kilobytes of the content provided by the io.Reader
+// like ReaderPreview and returns the full Result.
+func ReaderPreviewDetailed(reader io.Reader, maxKB int) (Result, error) {
+	if err := validatePreviewKB(maxKB); err != nil {
+		return Result{IsPlaintext: true}, err
+	}
 
-	if maxKB == 0 {
-		return true, errors.New("invalid length: maxKB must be greater than 0")
+	// A negative limit from NoLimit leaves the buffer sized to the reader.
+	return check(limitPreview(reader, maxKB), newReadBuffer(reader, previewLimit(maxKB)), buildOptions(nil))
+}
+
+// ReaderWithBuffer checks if the content provided by the io.Reader is plaintext,
+// using scratch as the read buffer so that repeated calls can avoid allocating.
+// If scratch is nil or too small to be useful, an internal buffer is allocated instead.
+func ReaderWithBuffer(reader io.Reader, scratch []byte) (bool, error) {
+	if len(scratch) < minScratchSize {
+		scratch = nil
+	}
+	return isPlaintextFromReader(reader, scratch)
+}
+
+// ReaderBounded checks if the entire content provided by the io.Reader is plaintext while never
+// holding more than windowBytes of it in memory at a time. Only the bytes of a rune split across
+// two reads are carried between windows, so memory use is constant regardless of stream length.
+func ReaderBounded(reader io.Reader, windowBytes int) (bool, error) {
+	if windowBytes <= 0 {
+		return true, fmt.Errorf("%w: windowBytes must be greater than 0", ErrInvalidLength)
+	}
+	return isPlaintextFromReader(reader, make([]byte, windowBytes))
+}
+
+// ByteReader checks if the content provided by the io.ByteReader, such as a bufio.Reader, is
+// plaintext. Bytes are consumed one at a time with ReadByte, and multibyte runes are reassembled
+// in a small fixed buffer, so no read buffer is allocated.
+func ByteReader(br io.ByteReader) (bool, error) {
+	o := buildOptions(nil)
+	if err := o.Validate(); err != nil {
+		return false, err
 	}
+	s := scanner{opts: o}
+	var b [1]byte
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+		b[0] = c
+		if !s.feed(b[:]) {
+			return false, nil
+		}
+	}
+	return s.finish(), nil
+}
 
-	limitedReader := io.LimitReader(reader, int64(maxBytes))
-	return isPlaintextFromReader(limitedReader)
+// IsValidUTF8 checks if the content provided by the io.Reader is valid UTF-8.
+// Unlike Reader, control characters are not rejected.
+func IsValidUTF8(reader io.Reader) (bool, error) {
+	res, err := scanReader(reader, nil, &scanner{utf8Only: true})
+	return res.IsPlaintext, err
+}
+
+// IsValidUTF8Bytes checks if the provided byte slice is valid UTF-8.
+// Unlike Bytes, control characters are not rejected.
+func IsValidUTF8Bytes(data []byte) (bool, error) {
+	// In-memory data: no IO error is expected.
+	s := scanner{utf8Only: true}
+	s.feed(data)
+	return s.finish(), nil
 }
@@ -1,9 +1,18 @@
 package isplaintextfile
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestPlaintextMethods(t *testing.T) {
@@ -88,6 +97,18 @@ func TestPlaintextMethods(t *testing.T) {
 		})
 	}
 
+	for _, tt := range tests {
+		t.Run("MustBytes_"+tt.name, func(t *testing.T) {
+			// The error-free variants must agree with Bytes.
+			if res := MustBytes(tt.content); res != tt.unlimitedExpected {
+				t.Errorf("MustBytes() = %v, want %v", res, tt.unlimitedExpected)
+			}
+			if res := MustString(string(tt.content)); res != tt.unlimitedExpected {
+				t.Errorf("MustString() = %v, want %v", res, tt.unlimitedExpected)
+			}
+		})
+	}
+
 	for _, tt := range tests {
 		t.Run("Reader_"+tt.name, func(t *testing.T) {
 			// 2. Test the io.Reader variant (unlimited).
@@ -203,3 +224,744 @@ func TestPlaintextMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestReaderWithBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"plain ASCII text", []byte("Hello, World!\n"), true},
+		{"binary content", []byte{0x00, 0x01, 0x02, 0x03}, false},
+		{"text with emoji", []byte("Hello 👋 World! 🌍\n"), true},
+		{"large text file", bytes.Repeat([]byte("Large plain text content\n"), 1000), true},
+		{"text after binary", append([]byte{0x00}, []byte("trailing text")...), false},
+	}
+
+	// The same scratch buffer is shared across every call.
+	scratch := make([]byte, 4*1024)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ReaderWithBuffer(bytes.NewReader(tt.content), scratch)
+			if err != nil {
+				t.Errorf("ReaderWithBuffer() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("ReaderWithBuffer() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	// A nil or undersized scratch buffer falls back to internal allocation.
+	for _, scratch := range [][]byte{nil, make([]byte, 1)} {
+		res, err := ReaderWithBuffer(bytes.NewReader([]byte("Hello 👋")), scratch)
+		if err != nil {
+			t.Errorf("ReaderWithBuffer() error: %v", err)
+		}
+		if !res {
+			t.Errorf("ReaderWithBuffer() with %d byte scratch = %v, want true", len(scratch), res)
+		}
+	}
+}
+
+func TestPreviewLimitDoesNotWrap(t *testing.T) {
+	// math.MaxInt32*1024 overflows a 32-bit int, so the limit must be computed as int64.
+	const maxKB = math.MaxInt32
+	want := int64(maxKB) * 1024
+	if got := previewLimit(maxKB); got != want || got <= 0 {
+		t.Errorf("previewLimit(%d) = %d, want %d", maxKB, got, want)
+	}
+
+	// A large limit must still read the entire content rather than wrapping to a tiny bound.
+	content := append(bytes.Repeat([]byte("A"), 4096), 0x00)
+	res, err := ReaderPreview(bytes.NewReader(content), maxKB)
+	if err != nil {
+		t.Errorf("ReaderPreview() error: %v", err)
+	}
+	if res {
+		t.Errorf("ReaderPreview() = %v, want false", res)
+	}
+}
+
+func TestIsValidUTF8(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       []byte
+		validUTF8     bool
+		plaintextRead bool
+	}{
+		{"plain ASCII text", []byte("Hello, World!\n"), true, true},
+		{"text with control character", []byte{'H', 'e', 'l', 'l', 'o', 0x07}, true, false},
+		{"NUL bytes", []byte{0x00, 0x00}, true, false},
+		{"text with emoji", []byte("Hello 👋 World! 🌍\n"), true, true},
+		{"invalid UTF-8", []byte{'H', 0xff, 'i'}, false, false},
+		{"truncated rune", []byte("Hello \xf0\x9f\x91"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := IsValidUTF8(bytes.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("IsValidUTF8() error: %v", err)
+			}
+			if res != tt.validUTF8 {
+				t.Errorf("IsValidUTF8() = %v, want %v", res, tt.validUTF8)
+			}
+
+			res, err = IsValidUTF8Bytes(tt.content)
+			if err != nil {
+				t.Errorf("IsValidUTF8Bytes() error: %v", err)
+			}
+			if res != tt.validUTF8 {
+				t.Errorf("IsValidUTF8Bytes() = %v, want %v", res, tt.validUTF8)
+			}
+
+			res, err = Reader(bytes.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("Reader() error: %v", err)
+			}
+			if res != tt.plaintextRead {
+				t.Errorf("Reader() = %v, want %v", res, tt.plaintextRead)
+			}
+		})
+	}
+}
+
+// opaqueReader hides the Len method of the wrapped reader.
+type opaqueReader struct {
+	r io.Reader
+}
+
+func (o opaqueReader) Read(p []byte) (int, error) {
+	return o.r.Read(p)
+}
+
+func BenchmarkReaderPreviewInMemory(b *testing.B) {
+	content := []byte("Hello, World!\n")
+
+	b.Run("bytes.Reader", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := ReaderPreview(bytes.NewReader(content), 64); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("opaque", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := ReaderPreview(opaqueReader{bytes.NewReader(content)}, 64); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkReaderDefault measures the scan with no options, which computes no optional metadata.
+func BenchmarkReaderDefault(b *testing.B) {
+	for _, bm := range []struct {
+		name    string
+		content []byte
+	}{
+		{"ASCII", bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog.\n"), 10<<20/45)},
+		{"multibyte", bytes.Repeat([]byte("Gr\u00fc\u00dfe aus Z\u00fcrich, \u4f60\u597d\u4e16\u754c.\n"), 10<<20/32)},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(int64(len(bm.content)))
+			for b.Loop() {
+				if isText, err := Reader(bytes.NewReader(bm.content)); err != nil || !isText {
+					b.Fatalf("Reader() = %v, %v", isText, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderBounded(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"text with emoji", []byte("Hello 👋 World! 🌍\n"), true},
+		{"binary at end", append(bytes.Repeat([]byte("text\n"), 1000), 0x00), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A window smaller than a rune still validates runes split across reads.
+			for _, window := range []int{1, 3, 64, 4096} {
+				res, err := ReaderBounded(bytes.NewReader(tt.content), window)
+				if err != nil {
+					t.Errorf("ReaderBounded() error: %v", err)
+				}
+				if res != tt.expected {
+					t.Errorf("ReaderBounded(%d) = %v, want %v", window, res, tt.expected)
+				}
+			}
+		})
+	}
+
+	if res, err := ReaderBounded(bytes.NewReader(nil), 0); !res || !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("ReaderBounded() with window 0 = %v, %v, want true, %v", res, err, ErrInvalidLength)
+	}
+}
+
+// repeatReader yields its pattern repeatedly until n bytes have been read, without allocating.
+type repeatReader struct {
+	pattern []byte
+	n       int64
+	off     int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+	total := 0
+	for total < len(p) {
+		c := copy(p[total:], r.pattern[r.off:])
+		total += c
+		r.off = (r.off + c) % len(r.pattern)
+	}
+	r.n -= int64(total)
+	return total, nil
+}
+
+func BenchmarkReaderBounded1GB(b *testing.B) {
+	pattern := []byte("Large plain text content with emoji 👋\n")
+	b.ReportAllocs()
+	b.SetBytes(1 << 30)
+	for b.Loop() {
+		// Memory per operation stays at the window size however long the stream is.
+		res, err := ReaderBounded(&repeatReader{pattern: pattern, n: 1 << 30}, 64*1024)
+		if err != nil || !res {
+			b.Fatalf("ReaderBounded() = %v, %v", res, err)
+		}
+	}
+}
+
+func TestFileRange(t *testing.T) {
+	header := []byte{0x89, 'F', 'M', 'T', 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b}
+	payload := []byte("text payload after a binary header\n")
+	content := append(append([]byte{}, header...), payload...)
+
+	tmpfile, err := os.CreateTemp("", "plaintext_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+	if _, err := tmpfile.Write(content); err != nil {
+		t.Fatalf("Failed to write to temp file: %v", err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		t.Fatalf("Failed to close temp file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		offset   int64
+		length   int64
+		expected bool
+	}{
+		{"payload to EOF", 16, 0, true},
+		{"payload with length", 16, int64(len(payload)), true},
+		{"part of payload", 20, 4, true},
+		{"whole file", 0, 0, false},
+		{"header only", 0, 16, false},
+		{"past EOF", 1000, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := FileRange(tmpfile.Name(), tt.offset, tt.length)
+			if err != nil {
+				t.Errorf("FileRange() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("FileRange(%d, %d) = %v, want %v", tt.offset, tt.length, res, tt.expected)
+			}
+		})
+	}
+
+	// Like the preview functions, invalid arguments report true along with the error.
+	if res, err := FileRange(tmpfile.Name(), -1, 0); !res || !errors.Is(err, ErrInvalidOffset) {
+		t.Errorf("FileRange() with negative offset = %v, %v, want true, %v", res, err, ErrInvalidOffset)
+	}
+}
+
+func TestPreviewLimits(t *testing.T) {
+	// Binary content beyond the first kilobyte is only seen without a limit.
+	content := append(bytes.Repeat([]byte("A"), 2048), 0x00)
+	path := filepath.Join(t.TempDir(), "preview")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		maxKB       int
+		expected    bool
+		expectedErr error
+	}{
+		{"one kilobyte", 1, true, nil},
+		{"unlimited", NoLimit, false, nil},
+		{"zero", 0, true, ErrInvalidLength},
+		{"negative", -5, true, ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := FilePreview(path, tt.maxKB)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("FilePreview(%d) error = %v, want %v", tt.maxKB, err, tt.expectedErr)
+			}
+			if err == nil && res != tt.expected {
+				t.Errorf("FilePreview(%d) = %v, want %v", tt.maxKB, res, tt.expected)
+			}
+
+			res, err = ReaderPreview(bytes.NewReader(content), tt.maxKB)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("ReaderPreview(%d) error = %v, want %v", tt.maxKB, err, tt.expectedErr)
+			}
+			if err == nil && res != tt.expected {
+				t.Errorf("ReaderPreview(%d) = %v, want %v", tt.maxKB, res, tt.expected)
+			}
+		})
+	}
+
+	// The limit is validated before the file is opened.
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := FilePreview(missing, 0); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("FilePreview() of a missing file with limit 0 error = %v, want %v", err, ErrInvalidLength)
+	}
+}
+
+func TestPreviewExaminesFirstByte(t *testing.T) {
+	text := bytes.Repeat([]byte("valid text\n"), 200)
+
+	// straddling puts a two-byte rune across the 1 KB limit after the prefix.
+	straddling := func(prefix []byte) []byte {
+		content := append(append([]byte{}, prefix...), bytes.Repeat([]byte("a"), 1023-len(prefix))...)
+		return append(content, "\u00e9 and more text\n"...)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"control character alone", []byte{0x07}},
+		{"control character before text", append([]byte{0x07}, text...)},
+		{"control character before multibyte text", append([]byte{0x07}, strings.Repeat("你好", 1000)...)},
+		{"NUL before text", append([]byte{0x00}, text...)},
+		{"control character before a rune cut by the limit", straddling([]byte{0x07})},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("first%d", i))
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			if res, err := FilePreview(path, 1); err != nil || res {
+				t.Errorf("FilePreview(1) = %v, %v, want false", res, err)
+			}
+			if res, err := ReaderPreview(bytes.NewReader(tt.content), 1); err != nil || res {
+				t.Errorf("ReaderPreview(1) = %v, %v, want false", res, err)
+			}
+			if res, err := ReaderPreview(opaqueReader{bytes.NewReader(tt.content)}, 1); err != nil || res {
+				t.Errorf("ReaderPreview(1) with opaque reader = %v, %v, want false", res, err)
+			}
+		})
+	}
+
+	// The rune cut by the limit is not held against the same content without the control character.
+	content := straddling(nil)
+	path := filepath.Join(dir, "straddling")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if res, err := FilePreview(path, 1); err != nil || !res {
+		t.Errorf("FilePreview(1) with a rune cut by the limit = %v, %v, want true", res, err)
+	}
+	if res, err := ReaderPreview(opaqueReader{iotest.OneByteReader(bytes.NewReader(content))}, 1); err != nil || !res {
+		t.Errorf("ReaderPreview(1) with a rune cut by the limit = %v, %v, want true", res, err)
+	}
+	if res, err := Check(bytes.NewReader(content), WithPreviewKB(1)); err != nil || !res.IsPlaintext {
+		t.Errorf("Check() with WithPreviewKB(1) and a rune cut by the limit = %+v, %v, want plaintext", res, err)
+	}
+}
+
+func TestFileMiddleSample(t *testing.T) {
+	text := bytes.Repeat([]byte("text header and footer\n"), 200)
+	binary := bytes.Repeat([]byte{0x00, 0x01, 0xfe, 0xff}, 1024)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"binary body", append(append(append([]byte{}, text...), binary...), text...), false},
+		{"text body", append(append(append([]byte{}, binary...), text...), binary...), true},
+		{"multibyte runes split at the middle", append([]byte("a"), strings.Repeat("你好", 1000)...), true},
+		{"small file", []byte("tiny\n"), true},
+		{"empty file", nil, true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("middle%d", i))
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			res, err := FileMiddleSample(path, 1)
+			if err != nil {
+				t.Errorf("FileMiddleSample() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("FileMiddleSample() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	// A preview of the start passes the file with a binary body.
+	path := filepath.Join(dir, "middle0")
+	if res, _ := FilePreview(path, 1); !res {
+		t.Errorf("FilePreview() = %v, want true", res)
+	}
+	if res, err := FileMiddleSample(path, 0); !res || !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("FileMiddleSample() with zero sampleKB = %v, %v, want true, %v", res, err, ErrInvalidLength)
+	}
+
+	// The sample is bounded by the file, not by a huge sampleKB.
+	tiny := filepath.Join(dir, "tiny")
+	if err := os.WriteFile(tiny, []byte("abcdefghijklmnopqrstuvwxyz"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if res, err := FileMiddleSample(tiny, math.MaxInt32); err != nil || !res {
+		t.Errorf("FileMiddleSample() with huge sampleKB = %v, %v, want true", res, err)
+	}
+}
+
+func TestSection(t *testing.T) {
+	payload := []byte("an archived text file\n")
+	archive := append(append(bytes.Repeat([]byte{0x00, 0xff}, 32), payload...), 0x00, 0x01, 0x02)
+	r := bytes.NewReader(archive)
+
+	tests := []struct {
+		name     string
+		offset   int64
+		length   int64
+		expected bool
+	}{
+		{"text entry", 64, int64(len(payload)), true},
+		{"part of text entry", 67, 8, true},
+		{"binary prefix", 0, 64, false},
+		{"entry overlapping binary", 64, int64(len(payload)) + 1, false},
+		{"empty entry", 64, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Section(r, tt.offset, tt.length)
+			if err != nil {
+				t.Errorf("Section() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("Section(%d, %d) = %v, want %v", tt.offset, tt.length, res, tt.expected)
+			}
+		})
+	}
+
+	if res, err := Section(r, -1, 4); !res || !errors.Is(err, ErrInvalidOffset) {
+		t.Errorf("Section() with negative offset = %v, %v, want true, %v", res, err, ErrInvalidOffset)
+	}
+	if res, err := Section(r, 0, -1); !res || !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Section() with negative length = %v, %v, want true, %v", res, err, ErrInvalidLength)
+	}
+}
+
+func TestByteReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"plain ASCII text", []byte("Hello, World!\n"), true},
+		{"text with emoji", []byte("Hello 👋 World! 🌍\n"), true},
+		{"text with Chinese characters", []byte("你好，世界！\n"), true},
+		{"binary content", []byte{0x00, 0x01, 0x02, 0x03}, false},
+		{"truncated rune", []byte("Hello \xf0\x9f\x91"), false},
+		{"empty", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ByteReader(bufio.NewReader(bytes.NewReader(tt.content)))
+			if err != nil {
+				t.Errorf("ByteReader() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("ByteReader() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	content := "\uFEFFname,city\n\tAlice,\"Zürich\"  \n\tBob,cafe\u0301\n"
+
+	res, err := Analyze(strings.NewReader(content),
+		WithEncoding(AutoDetect),
+		WithSnippet(4),
+		WithDetectScript(),
+		WithDetectIndentation(),
+		WithTrackTrailingWhitespace(),
+		WithRequireNFC(),
+		WithDetectDelimited(),
+	)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+
+	want := Result{
+		IsPlaintext:             true,
+		Classification:          PlainText,
+		Encoding:                UTF8,
+		Confidence:              1,
+		RuneCount:               40,
+		Lines:                   3,
+		TrailingWhitespaceLines: []int{2},
+		TrailingWhitespaceCount: 1,
+		IndentStyle:             Tabs,
+		Script:                  "Latin",
+		NotNormalized:           true,
+		Delimited:               true,
+		Delimiter:               ',',
+		Columns:                 2,
+		FinalNewline:            true,
+		PrintableRatio:          1,
+		Snippet:                 "\uFEFFnam",
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("Analyze() = %+v, want %+v", res, want)
+	}
+
+	// Without options only the verdict, counts, and ratios are reported.
+	res, err = Analyze(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if res.Snippet != "" || res.Script != "" || res.IndentStyle != None || res.TrailingWhitespaceLines != nil ||
+		res.NotNormalized || res.Delimited || res.Confidence != 0 {
+		t.Errorf("Analyze() without options = %+v, want no optional metadata", res)
+	}
+}
+
+func TestSameClassification(t *testing.T) {
+	root := writeTree(t, map[string][]byte{
+		"original.txt":    []byte("line one\r\nline two\r\n"),
+		"transformed.txt": []byte("line one\nline two\n"),
+		"image.png":       {0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00},
+		"archive.bin":     {0x00, 0x01, 0x02, 0x03},
+	})
+
+	tests := []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{"text and text", "original.txt", "transformed.txt", true},
+		{"text and binary", "original.txt", "image.png", false},
+		{"binary and text", "image.png", "transformed.txt", false},
+		{"binary and binary", "image.png", "archive.bin", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := SameClassification(filepath.Join(root, tt.a), filepath.Join(root, tt.b))
+			if err != nil {
+				t.Errorf("SameClassification() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("SameClassification() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	if _, err := SameClassification(filepath.Join(root, "original.txt"), filepath.Join(root, "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("SameClassification() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestFileTailPreview(t *testing.T) {
+	binary := bytes.Repeat([]byte{0x00, 0x01, 0xfe, 0xff}, 1024)
+	text := bytes.Repeat([]byte("2024-01-01 INFO request served\n"), 100)
+
+	tests := []struct {
+		name     string
+		content  []byte
+		maxKB    int
+		expected bool
+	}{
+		{"binary head and text tail", append(append([]byte{}, binary...), text...), 1, true},
+		{"text head and binary tail", append(append([]byte{}, text...), binary...), 1, false},
+		{"whole file with NoLimit", append(append([]byte{}, binary...), text...), NoLimit, false},
+		{"file smaller than the window", []byte("short log\n"), 4, true},
+		{"binary file smaller than the window", []byte{0x00, 0x01}, 4, false},
+		{"window starting inside a rune", append([]byte{0x00}, strings.Repeat("你好", 1000)...), 1, true},
+		{"empty file", nil, 1, true},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("tail%d", i))
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			res, err := FileTailPreview(path, tt.maxKB)
+			if err != nil {
+				t.Errorf("FileTailPreview() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("FileTailPreview() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	if _, err := FileTailPreview(filepath.Join(dir, "tail0"), 0); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("FileTailPreview() error = %v, want %v", err, ErrInvalidLength)
+	}
+}
+
+func TestDetailedAgreesWithBoolean(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"empty", []byte{}},
+		{"text", []byte("Hello, World!\nsecond line\n")},
+		{"multibyte text", []byte("你好, 世界\n")},
+		{"binary", []byte{0x89, 'P', 'N', 'G', 0x00, 0x01}},
+		{"invalid UTF-8", []byte{'a', 0xff, 'b'}},
+		{"text then binary beyond preview", append(bytes.Repeat([]byte("a"), 2048), 0x00)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "content")
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			pairs := []struct {
+				name     string
+				boolean  func() (bool, error)
+				detailed func() (Result, error)
+			}{
+				{"Bytes",
+					func() (bool, error) { return Bytes(tt.content) },
+					func() (Result, error) { return BytesDetailed(tt.content) }},
+				{"File",
+					func() (bool, error) { return File(path) },
+					func() (Result, error) { return FileDetailed(path) }},
+				{"Reader",
+					func() (bool, error) { return Reader(bytes.NewReader(tt.content)) },
+					func() (Result, error) { return ReaderDetailed(bytes.NewReader(tt.content)) }},
+				{"FilePreview",
+					func() (bool, error) { return FilePreview(path, 1) },
+					func() (Result, error) { return FilePreviewDetailed(path, 1) }},
+				{"ReaderPreview",
+					func() (bool, error) { return ReaderPreview(bytes.NewReader(tt.content), 1) },
+					func() (Result, error) { return ReaderPreviewDetailed(bytes.NewReader(tt.content), 1) }},
+			}
+			for _, p := range pairs {
+				isText, err := p.boolean()
+				if err != nil {
+					t.Fatalf("%s() error: %v", p.name, err)
+				}
+				res, err := p.detailed()
+				if err != nil {
+					t.Fatalf("%sDetailed() error: %v", p.name, err)
+				}
+				if res.IsPlaintext != isText {
+					t.Errorf("%sDetailed().IsPlaintext = %v, want %v", p.name, res.IsPlaintext, isText)
+				}
+			}
+		})
+	}
+
+	res, err := BytesDetailed([]byte("one\ntwo\n"))
+	if err != nil || res.Lines != 2 || res.RuneCount != 8 {
+		t.Errorf("BytesDetailed() = %+v, %v, want 2 lines and 8 runes", res, err)
+	}
+
+	if _, err := ReaderPreviewDetailed(strings.NewReader("text"), 0); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("ReaderPreviewDetailed() error = %v, want %v", err, ErrInvalidLength)
+	}
+}
+
+func TestMinStablePreview(t *testing.T) {
+	binaryAt := func(size, offset int) []byte {
+		content := bytes.Repeat([]byte("a"), size)
+		content[offset] = 0x00
+		return content
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		kb      int
+		verdict bool
+	}{
+		{"binary in the third kilobyte", binaryAt(8*1024, 2*1024+10), 3, false},
+		{"binary at the start", binaryAt(4*1024, 0), 1, false},
+		{"binary in the last byte of a kilobyte", binaryAt(4*1024, 2*1024-1), 2, false},
+		{"text", bytes.Repeat([]byte("line\n"), 500), 3, true},
+		{"text of whole kilobytes", bytes.Repeat([]byte("a"), 2*1024), 2, true},
+		{"empty", []byte{}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "content")
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			kb, verdict, err := MinStablePreview(path)
+			if err != nil {
+				t.Fatalf("MinStablePreview() error: %v", err)
+			}
+			if kb != tt.kb || verdict != tt.verdict {
+				t.Errorf("MinStablePreview() = %d, %v, want %d, %v", kb, verdict, tt.kb, tt.verdict)
+			}
+
+			// Previews of kb kilobytes and more agree with the full check.
+			for _, size := range []int{kb, kb + 1} {
+				if res, _ := FilePreview(path, size); res != tt.verdict {
+					t.Errorf("FilePreview(%d) = %v, want %v", size, res, tt.verdict)
+				}
+			}
+			if !tt.verdict && kb > 1 {
+				if res, _ := FilePreview(path, kb-1); !res {
+					t.Errorf("FilePreview(%d) = false, want true before the binary byte", kb-1)
+				}
+			}
+		})
+	}
+
+	if _, _, err := MinStablePreview(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("MinStablePreview() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,27 @@
+package isplaintextfile
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+)
+
+// resultLine is a single line of JSON Lines output: the path followed by the result fields.
+type resultLine struct {
+	Path string
+	Result
+}
+
+// WriteResultsJSONL writes the results of a batch check, such as from WalkDir, to w as JSON Lines:
+// one self-contained JSON object per line holding the path and the fields of its Result. This suits
+// streaming into log processors and tools like jq. The lines are written in path order.
+func WriteResultsJSONL(w io.Writer, results map[string]Result) error {
+	enc := json.NewEncoder(w)
+	for _, path := range slices.Sorted(maps.Keys(results)) {
+		if err := enc.Encode(resultLine{Path: path, Result: results[path]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package isplaintextfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteResultsJSONL(t *testing.T) {
+	results := map[string]Result{
+		"docs/readme.md": {IsPlaintext: true, Encoding: UTF8, RuneCount: 42, Lines: 3},
+		"bin/tool":       {IsPlaintext: false, Encoding: UTF8, InvalidBytes: 1},
+		"big.iso":        {Reason: SkippedTooLarge},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteResultsJSONL(&buf, results); err != nil {
+		t.Fatalf("WriteResultsJSONL() error: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line struct {
+			Path        string
+			IsPlaintext bool
+			Encoding    Encoding
+			RuneCount   int64
+			Reason      Reason
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q does not parse as JSON: %v", scanner.Text(), err)
+		}
+
+		want, ok := results[line.Path]
+		if !ok {
+			t.Fatalf("unexpected path %q", line.Path)
+		}
+		seen[line.Path] = true
+		if line.IsPlaintext != want.IsPlaintext || line.Encoding != want.Encoding ||
+			line.RuneCount != want.RuneCount || line.Reason != want.Reason {
+			t.Errorf("line for %q = %+v, want fields of %+v", line.Path, line, want)
+		}
+	}
+	if len(seen) != len(results) {
+		t.Errorf("WriteResultsJSONL() wrote %d lines, want %d", len(seen), len(results))
+	}
+}
@@ -0,0 +1,39 @@
+package isplaintextfile
+
+// LineEndings counts the line breaks of each kind in the content.
+type LineEndings struct {
+	LF   int
+	CRLF int
+	CR   int
+}
+
+// Mixed reports whether more than one kind of line break is present.
+func (le LineEndings) Mixed() bool {
+	kinds := 0
+	for _, n := range []int{le.LF, le.CRLF, le.CR} {
+		if n > 0 {
+			kinds++
+		}
+	}
+	return kinds > 1
+}
+
+// normalize counts the line break r, if it is one, and returns the rune line-based metrics see in
+// its place: a CR becomes an LF, and the LF of a CRLF pair is dropped, reported by ok being false,
+// so every kind of line break reaches them as a single LF. afterCR reports that the previous rune
+// was a CR.
+func (le *LineEndings) normalize(r rune, afterCR bool) (metric rune, ok bool) {
+	switch {
+	case r == '\n' && afterCR:
+		// The CR was counted as a lone CR until now.
+		le.CR--
+		le.CRLF++
+		return 0, false
+	case r == '\n':
+		le.LF++
+	case r == '\r':
+		le.CR++
+		return '\n', true
+	}
+	return r, true
+}
@@ -0,0 +1,18 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+)
+
+// MultipartFile checks if up to maxKB kilobytes of an uploaded file are plaintext, then seeks
+// the file back to the start so the upload can still be saved or processed by the handler. The
+// file is rewound even if the check fails, and an error from seeking is joined to that of the check.
+func MultipartFile(f multipart.File, maxKB int) (bool, error) {
+	res, err := ReaderPreview(f, maxKB)
+	if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
+		return false, errors.Join(err, seekErr)
+	}
+	return res, err
+}
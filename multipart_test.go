@@ -0,0 +1,65 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// uploadFile is an in-memory multipart.File.
+type uploadFile struct {
+	*bytes.Reader
+}
+
+func (uploadFile) Close() error { return nil }
+
+func TestMultipartFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"plain ASCII text", []byte("Hello, World!\n"), true},
+		{"binary content", []byte{0x00, 0x01, 0x02, 0x03}, false},
+		{"binary after preview", append(bytes.Repeat([]byte("A"), 1024), 0x00), true},
+		{"multibyte rune cut by the preview", append(bytes.Repeat([]byte("a"), 1023), "\u00e9 and more text"...), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := uploadFile{bytes.NewReader(tt.content)}
+
+			res, err := MultipartFile(f, 1)
+			if err != nil {
+				t.Errorf("MultipartFile() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("MultipartFile() = %v, want %v", res, tt.expected)
+			}
+
+			// The whole upload must still be readable from the start.
+			saved, err := io.ReadAll(f)
+			if err != nil {
+				t.Fatalf("io.ReadAll() error: %v", err)
+			}
+			if !bytes.Equal(saved, tt.content) {
+				t.Errorf("upload after MultipartFile() = %q, want %q", saved, tt.content)
+			}
+		})
+	}
+}
+
+func TestMultipartFileRewindsOnError(t *testing.T) {
+	f := uploadFile{bytes.NewReader([]byte("Hello, World!\n"))}
+	if _, err := f.Seek(7, io.SeekStart); err != nil {
+		t.Fatalf("Seek() error: %v", err)
+	}
+
+	if _, err := MultipartFile(f, 0); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("MultipartFile() error = %v, want %v", err, ErrInvalidLength)
+	}
+	if pos, _ := f.Seek(0, io.SeekCurrent); pos != 0 {
+		t.Errorf("position after MultipartFile() = %d, want 0", pos)
+	}
+}
@@ -0,0 +1,46 @@
+package isplaintextfile
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxNFCCarry bounds the bytes held back between chunks while checking normalization. A
+// sequence of combining marks longer than this is checked in pieces.
+const maxNFCCarry = 4096
+
+// nfcCheck reports whether streamed UTF-8 content is in Unicode Normalization Form C. Content is
+// checked up to the last normalization boundary of each chunk, so that a character and the
+// combining marks following it in the next chunk are checked together.
+type nfcCheck struct {
+	pending       []byte
+	notNormalized bool
+}
+
+// write checks the next chunk of content.
+func (c *nfcCheck) write(chunk []byte) {
+	if c.notNormalized {
+		return
+	}
+	c.pending = append(c.pending, chunk...)
+	end := norm.NFC.LastBoundary(c.pending)
+	if end <= 0 {
+		if len(c.pending) <= maxNFCCarry {
+			return
+		}
+		end = len(c.pending)
+	}
+	if !norm.NFC.IsNormal(c.pending[:end]) {
+		c.notNormalized = true
+	}
+	c.pending = append(c.pending[:0], c.pending[end:]...)
+}
+
+// normal checks the content held back at the end of the content and reports whether all of it
+// was in Normalization Form C.
+func (c *nfcCheck) normal() bool {
+	if !c.notNormalized && len(c.pending) > 0 {
+		c.notNormalized = !norm.NFC.IsNormal(c.pending)
+		c.pending = nil
+	}
+	return !c.notNormalized
+}
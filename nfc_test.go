@@ -0,0 +1,58 @@
+package isplaintextfile
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWithRequireNFC(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		notNormalized bool
+	}{
+		{"composed é", "café\n", false},
+		{"decomposed é", "cafe\u0301\n", true},
+		{"decomposed é at the end", "cafe\u0301", true},
+		{"ASCII", "plain text\n", false},
+		{"Hangul syllables", "한국어\n", false},
+		{"Hangul jamo", "\u1112\u1161\u11ab\n", true},
+		{"combining mark without a base", "\u0301abc\n", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithRequireNFC())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if !res.IsPlaintext || res.NotNormalized != tt.notNormalized {
+				t.Errorf("Check() = %+v, want plaintext with NotNormalized %v", res, tt.notNormalized)
+			}
+
+			// A character and its combining marks are checked together across reads.
+			res, err = Check(iotest.OneByteReader(strings.NewReader(tt.content)), WithRequireNFC())
+			if err != nil {
+				t.Errorf("Check() with one-byte reads error: %v", err)
+			}
+			if res.NotNormalized != tt.notNormalized {
+				t.Errorf("Check() with one-byte reads NotNormalized = %v, want %v", res.NotNormalized, tt.notNormalized)
+			}
+
+			res, err = Check(strings.NewReader(tt.content), WithRejectUnnormalized())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext == tt.notNormalized {
+				t.Errorf("Check() with WithRejectUnnormalized() = %v, want %v", res.IsPlaintext, !tt.notNormalized)
+			}
+		})
+	}
+
+	// Normalization is not checked by default.
+	if res, _ := Check(strings.NewReader("cafe\u0301\n")); res.NotNormalized {
+		t.Errorf("Check().NotNormalized = %v, want false", res.NotNormalized)
+	}
+}
@@ -0,0 +1,25 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Normalize reads the content of the reader and, if it is plaintext, returns it with a leading
+// byte order mark stripped and CRLF and lone CR line endings converted to LF, as when pasting
+// text into an editor. For content that is not plaintext, ok is false and text is empty.
+func Normalize(reader io.Reader) (text string, ok bool, err error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", false, err
+	}
+	if res, err := checkBytes(data, buildOptions(nil)); !res.IsPlaintext {
+		return "", false, err
+	}
+
+	text = string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return text, true, nil
+}
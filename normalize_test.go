@@ -0,0 +1,37 @@
+package isplaintextfile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		expected   string
+		expectedOK bool
+	}{
+		{"CRLF with BOM", "\xef\xbb\xbfline one\r\nline two\r\n", "line one\nline two\n", true},
+		{"mixed line endings", "a\r\nb\rc\nd", "a\nb\nc\nd", true},
+		{"already normalized", "Hello, World!\n", "Hello, World!\n", true},
+		{"empty", "", "", true},
+		{"interior BOM kept", "a\xef\xbb\xbfb", "a\uFEFFb", true},
+		{"binary", "\x00\x01\x02\x03", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, ok, err := Normalize(strings.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("Normalize() error: %v", err)
+			}
+			if ok != tt.expectedOK {
+				t.Errorf("Normalize() ok = %v, want %v", ok, tt.expectedOK)
+			}
+			if text != tt.expected {
+				t.Errorf("Normalize() = %q, want %q", text, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,841 @@
+package isplaintextfile
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Options configures how content is classified.
+type Options struct {
+	// BestEffortOnError returns the verdict for the bytes read so far when a read fails mid-stream.
+	BestEffortOnError bool
+
+	// AllowedCategories, when non-empty, restricts accepted runes to those in at least one of the tables.
+	AllowedCategories []*unicode.RangeTable
+
+	// CharsetDetector names the encoding of a sample of the content so it can be decoded before validation.
+	CharsetDetector func(sample []byte) (encoding string, confidence float64)
+
+	// RejectInteriorBOM rejects a U+FEFF byte order mark anywhere except the start of the content.
+	RejectInteriorBOM bool
+
+	// AllowedControlChars lists C0 control characters accepted in addition to tab, line feed, and carriage return.
+	AllowedControlChars []byte
+
+	// RespectDeclaredEncoding decodes content using the encoding declared by a leading XML prolog or HTML meta tag.
+	RespectDeclaredEncoding bool
+
+	// MaxFileSize, when positive, classifies files larger than this many bytes as not plaintext without reading them.
+	MaxFileSize int64
+
+	// MaxInvalidBytes is the number of bytes that are not valid UTF-8 tolerated before content is rejected.
+	MaxInvalidBytes int
+
+	// Encoding, when set, decodes content from this encoding instead of detecting it.
+	Encoding Encoding
+
+	// StrictEOF fails the check if the reader returns data after it has returned io.EOF.
+	StrictEOF bool
+
+	// MaxTeeBuffer, when positive, limits how many bytes ReaderTee buffers while classifying.
+	MaxTeeBuffer int64
+
+	// TrailingToleranceBytes is the number of arbitrary bytes at the end of otherwise valid content that are ignored.
+	TrailingToleranceBytes int
+
+	// RecognizeANSIEscapes accepts well-formed ANSI CSI escape sequences as text.
+	RecognizeANSIEscapes bool
+
+	// PrintableThreshold, when positive, accepts content whose printable ratio is at least this value
+	// instead of rejecting it at the first disallowed character.
+	PrintableThreshold float64
+
+	// NULFastReject rejects content at the first NUL byte, even under a printable threshold.
+	NULFastReject bool
+
+	// TimeBudget, when positive, limits how long a check spends reading and scanning.
+	TimeBudget time.Duration
+
+	// FollowSymlinks makes WalkDir follow symbolic links to files and directories.
+	FollowSymlinks bool
+
+	// SnippetRunes is how many leading runes of plaintext content are captured in Result.Snippet.
+	SnippetRunes int
+
+	// ControlReplacements maps control characters to the bytes Sanitize replaces them with.
+	ControlReplacements map[byte][]byte
+
+	// MinPrintableRatio, when positive, rejects content in which fewer than this fraction of the
+	// decoded runes are graphic characters or whitespace.
+	MinPrintableRatio float64
+
+	// StopWhenTextBytes, when positive, accepts content early once this many bytes of clean text
+	// have been scanned.
+	StopWhenTextBytes int64
+
+	// UnicodeLineSeparators accepts NEL, LINE SEPARATOR, and PARAGRAPH SEPARATOR as line breaks.
+	UnicodeLineSeparators bool
+
+	// TrackTrailingWhitespace reports the lines that end in spaces or tabs.
+	TrackTrailingWhitespace bool
+
+	// SingleReadDecision decides from the content returned by a single Read call.
+	SingleReadDecision bool
+
+	// StopCondition, when set, is consulted after each read and stops the scan when it returns true.
+	StopCondition func(stats ScanStats) bool
+
+	// ForceText and ForceBinary hold glob patterns for file paths classified without reading them.
+	ForceText   []string
+	ForceBinary []string
+
+	// DetectIndentation reports the indentation style of the content.
+	DetectIndentation bool
+
+	// HardFailAfter, when positive, rejects content as soon as this many disallowed runes and
+	// invalid bytes have been seen, regardless of any tolerance.
+	HardFailAfter int
+
+	// Progress, when set, is called with the number of bytes read after each read.
+	Progress func(bytesRead int64)
+
+	// RejectAstral rejects runes outside the Basic Multilingual Plane.
+	RejectAstral bool
+
+	// DetectScript reports the dominant Unicode script of the content.
+	DetectScript bool
+
+	// ASCIIFastPath validates ASCII content without the per-rune bookkeeping until the first
+	// byte outside ASCII.
+	ASCIIFastPath bool
+
+	// RequireNFC reports content that is not in Unicode Normalization Form C, and
+	// RejectUnnormalized also rejects it.
+	RequireNFC         bool
+	RejectUnnormalized bool
+
+	// DetectDelimited reports content whose lines are split into the same number of fields by a
+	// delimiter.
+	DetectDelimited bool
+
+	// MaxDecompressedBytes limits how many bytes of compressed content are decompressed.
+	MaxDecompressedBytes int64
+
+	// MinPrintableRun requires a run of at least this many consecutive printable runes.
+	MinPrintableRun int64
+
+	// PreviewKB, when positive, limits a check to the first this many kilobytes of the content.
+	PreviewKB int
+
+	// MaxConsecutiveInvalid, when positive, rejects content with this many invalid bytes or
+	// replacement characters in a row.
+	MaxConsecutiveInvalid int
+
+	// DetectByteWidth reports whether the text fits in 7 bits, 8 bits, or needs multibyte storage.
+	DetectByteWidth bool
+
+	// DetectTemplateMarkers reports text containing unrendered template markers.
+	DetectTemplateMarkers bool
+
+	// ControlDensityWindow, when positive, tolerates control characters unless more than
+	// ControlDensityMaxRatio of the bytes in a window of this size belong to them.
+	ControlDensityWindow   int
+	ControlDensityMaxRatio float64
+
+	// DetectStructuredIndent reports text that looks like YAML or Python.
+	DetectStructuredIndent bool
+
+	// MaxOpenFiles limits how many files the checks sharing one WithMaxOpenFiles option hold open
+	// at a time. openFiles is the semaphore they share.
+	MaxOpenFiles int
+	openFiles    chan struct{}
+
+	// RejectFormatChars rejects invisible format characters in Unicode category Cf.
+	RejectFormatChars bool
+
+	// CountWords reports the number of whitespace-separated words in plaintext.
+	CountWords bool
+
+	// IdleTimeout, when positive, ends the content once no bytes arrive for that long.
+	IdleTimeout time.Duration
+
+	// NormalizeForMetrics feeds line-based metrics a single LF for every kind of line break.
+	NormalizeForMetrics bool
+
+	// encodingSet reports that an encoding was chosen by the options applied after the defaults,
+	// so that choosing a different one can be reported. conflict holds the first such conflict.
+	encodingSet bool
+	conflict    *OptionError
+
+	// allowedASCII caches the policy for ASCII runes, so the scan of mostly-ASCII content looks
+	// each byte up instead of evaluating the policy. It is built by buildOptions.
+	allowedASCII *asciiTable
+
+	// runeMetadata reports that an option computes metadata from every accepted rune, so the scan
+	// of content without any can skip it. It is set by buildOptions.
+	runeMetadata bool
+
+	// unconfigured reports that the options were built without any default or per-call options.
+	unconfigured bool
+}
+
+// OptionError describes two options that contradict each other. Rather than one silently taking
+// precedence, checks fail with an OptionError.
+type OptionError struct {
+	// Option and Conflict are the contradicting options, such as "WithNULFastReject()".
+	Option   string
+	Conflict string
+
+	// Reason explains the contradiction.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("conflicting options %s and %s: %s", e.Option, e.Conflict, e.Reason)
+}
+
+// Validate reports the first contradiction among the options as an *OptionError, or nil if
+// there is none. Checks validate their options before reading any content.
+func (o Options) Validate() error {
+	if o.conflict != nil {
+		return o.conflict
+	}
+	if o.NULFastReject && slices.Contains(o.AllowedControlChars, 0) {
+		return &OptionError{
+			Option:   "WithNULFastReject()",
+			Conflict: "WithAllowedControlChars(0)",
+			Reason:   "NUL cannot be both allowed and rejected",
+		}
+	}
+	if o.RespectDeclaredEncoding && o.Encoding != Unknown && o.Encoding != AutoDetect {
+		return &OptionError{
+			Option:   "WithRespectDeclaredEncoding()",
+			Conflict: fmt.Sprintf("WithEncoding(%s)", o.Encoding),
+			Reason:   "a fixed encoding overrides any declared encoding",
+		}
+	}
+	return nil
+}
+
+// Option modifies the Options used for a check.
+type Option func(*Options)
+
+var (
+	defaultOptionsMu sync.RWMutex
+	defaultOptions   []Option
+
+	// defaultBuilt is the result of building the default options, with its ASCII table, so calls
+	// without per-call options do not build them again. It is refreshed by SetDefaultOptions.
+	defaultBuilt = buildDefaults(nil)
+)
+
+// SetDefaultOptions replaces the package-level default options. The defaults are applied
+// before any per-call options and are used by the functions that take no options, such as
+// File, Reader, and Bytes. The change affects every caller in the process; it is safe to call
+// concurrently with checks, which use the defaults in effect when they start. Calling
+// SetDefaultOptions with no options restores the original behavior.
+func SetDefaultOptions(opts ...Option) {
+	defaults := append([]Option(nil), opts...)
+	built := buildDefaults(defaults)
+
+	defaultOptionsMu.Lock()
+	defaultOptions = defaults
+	defaultBuilt = built
+	defaultOptionsMu.Unlock()
+}
+
+// buildOptions applies the given options over the defaults.
+func buildOptions(opts []Option) Options {
+	defaultOptionsMu.RLock()
+	defaults, base := defaultOptions, defaultBuilt
+	defaultOptionsMu.RUnlock()
+
+	if len(opts) == 0 {
+		return base
+	}
+	return completeOptions(applyOptions(defaults, opts), &base)
+}
+
+// buildDefaults builds the default options on their own.
+func buildDefaults(defaults []Option) Options {
+	o := completeOptions(applyOptions(defaults, nil), nil)
+	o.unconfigured = len(defaults) == 0
+	return o
+}
+
+// applyOptions applies the per-call options over the defaults.
+func applyOptions(defaults, opts []Option) Options {
+	var o Options
+	for _, opt := range defaults {
+		opt(&o)
+	}
+	// Per-call options may override the default encoding.
+	o.encodingSet = false
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// completeOptions sets the fields derived from the options. The ASCII table of base is reused
+// when the options leave the policy for ASCII runes as it is in base.
+func completeOptions(o Options, base *Options) Options {
+	if base != nil && slices.Equal(o.AllowedControlChars, base.AllowedControlChars) &&
+		slices.Equal(o.AllowedCategories, base.AllowedCategories) {
+		o.allowedASCII = base.allowedASCII
+	} else {
+		o.allowedASCII = newASCIITable(o)
+	}
+	o.runeMetadata = o.DetectIndentation || o.DetectStructuredIndent || o.DetectDelimited ||
+		o.NormalizeForMetrics || o.DetectScript || o.DetectTemplateMarkers || o.CountWords ||
+		o.DetectByteWidth || o.MinPrintableRun > 0 || o.MinPrintableRatio > 0 || o.StopWhenTextBytes > 0
+	return o
+}
+
+// WithBestEffortOnError makes a read error that occurs after some content was read non-fatal.
+// The verdict covers only the bytes read and Result.Incomplete is set. The errors of limits and
+// checks set by other options, ErrTeeBufferExceeded, ErrDecompressionLimitExceeded, and
+// ErrDataAfterEOF, are still returned.
+func WithBestEffortOnError() Option {
+	return func(o *Options) {
+		o.BestEffortOnError = true
+	}
+}
+
+// WithUnicodeCategory restricts accepted runes to those in at least one of the given tables,
+// such as unicode.L, unicode.N, and unicode.White_Space. Any other rune causes rejection.
+// The control character policy still applies to runes in the allowed tables.
+func WithUnicodeCategory(allowed ...*unicode.RangeTable) Option {
+	return func(o *Options) {
+		o.AllowedCategories = append(o.AllowedCategories, allowed...)
+	}
+}
+
+// WithCharsetDetector plugs in an external charset detector. The detector receives a sample of
+// the leading content and returns an encoding name, such as "ISO-8859-1" or "windows-1252".
+// The content is decoded from that encoding to UTF-8 before the plaintext check runs.
+// Names that are UTF-8 or unrecognized leave the content to be validated as UTF-8.
+func WithCharsetDetector(detector func(sample []byte) (encoding string, confidence float64)) Option {
+	return func(o *Options) {
+		o.CharsetDetector = detector
+	}
+}
+
+// WithRejectInteriorBOM rejects content containing a U+FEFF byte order mark anywhere other
+// than at the very start, which usually indicates accidentally concatenated files.
+// A leading byte order mark is unaffected.
+func WithRejectInteriorBOM() Option {
+	return func(o *Options) {
+		o.RejectInteriorBOM = true
+	}
+}
+
+// WithAllowedControlChars accepts the given C0 control characters, such as form feed ('\f'),
+// in addition to tab, line feed, and carriage return.
+func WithAllowedControlChars(chars ...byte) Option {
+	return func(o *Options) {
+		o.AllowedControlChars = append(o.AllowedControlChars, chars...)
+	}
+}
+
+// WithRespectDeclaredEncoding decodes content using the charset declared by an XML prolog
+// (<?xml version="1.0" encoding="..."?>) or an HTML meta tag near the start of the content.
+// When there is no declaration or the charset is unknown, the charset detector is used if one
+// is configured; otherwise the content is validated as UTF-8.
+func WithRespectDeclaredEncoding() Option {
+	return func(o *Options) {
+		o.RespectDeclaredEncoding = true
+	}
+}
+
+// WithMaxFileSize classifies files larger than n bytes as not plaintext without reading them,
+// on the assumption that very large files are binary. The file is only inspected with a stat,
+// and Result.Reason is set to SkippedTooLarge. It applies to checks of files by path.
+func WithMaxFileSize(n int64) Option {
+	return func(o *Options) {
+		o.MaxFileSize = n
+	}
+}
+
+// WithMaxInvalidBytes tolerates up to n bytes that are not valid UTF-8, such as the remains of
+// a truncated paste, skipping them before declaring the content binary. The count is an absolute
+// number of bytes and is reported in Result.InvalidBytes.
+func WithMaxInvalidBytes(n int) Option {
+	return func(o *Options) {
+		o.MaxInvalidBytes = n
+	}
+}
+
+// WithEncoding decodes content from the given encoding, such as Latin1 or EBCDIC, before the
+// control character policy is applied to the decoded runes. Detection is skipped. Any encoding
+// name or alias known to IANA is accepted; checks fail with ErrUnsupportedEncoding otherwise.
+// AutoDetect instead chooses the encoding from the content. Passing two different encodings fails
+// the check with an *OptionError, although a per-call encoding overrides a default one.
+func WithEncoding(enc Encoding) Option {
+	return func(o *Options) {
+		if o.encodingSet && !sameEncoding(o.Encoding, enc) && o.conflict == nil {
+			o.conflict = &OptionError{
+				Option:   fmt.Sprintf("WithEncoding(%s)", o.Encoding),
+				Conflict: fmt.Sprintf("WithEncoding(%s)", enc),
+				Reason:   "only one encoding can be used",
+			}
+		}
+		o.Encoding = enc
+		o.encodingSet = true
+	}
+}
+
+// WithCodePage decodes content from the numbered IBM or Windows code page, such as 37 for
+// EBCDIC or 1252 for Windows Western European. It is shorthand for WithEncoding.
+func WithCodePage(cp int) Option {
+	return WithEncoding(codePageEncoding(cp))
+}
+
+// WithStrictEOF treats a reader that returns data after it has returned io.EOF as broken and
+// fails the check with ErrDataAfterEOF. This catches buggy custom readers in tests and pipelines.
+// It is off by default to match the lenient io.Reader contract.
+func WithStrictEOF() Option {
+	return func(o *Options) {
+		o.StrictEOF = true
+	}
+}
+
+// WithMaxTeeBuffer limits ReaderTee to buffering at most n bytes of consumed content. If the
+// classification needs to read further, ReaderTee returns ErrTeeBufferExceeded instead of
+// growing its buffer, protecting servers from running out of memory on large inputs. Content of
+// exactly n bytes fits; one byte is read past the limit to tell, and kept for the replay.
+func WithMaxTeeBuffer(n int64) Option {
+	return func(o *Options) {
+		o.MaxTeeBuffer = n
+	}
+}
+
+// WithTrailingToleranceBytes ignores up to n bytes of arbitrary non-text content at the very end
+// of otherwise valid plaintext, such as editor metadata appended to a text file. Content after
+// the first rejected byte is not validated, only counted; if the content ends within n bytes of
+// it, the content is plaintext and Result.TrailingIgnored reports the ignored length.
+func WithTrailingToleranceBytes(n int) Option {
+	return func(o *Options) {
+		o.TrailingToleranceBytes = n
+	}
+}
+
+// WithRecognizeANSIEscapes accepts well-formed ANSI CSI escape sequences, such as the SGR
+// sequences that color terminal output, as text. A bare ESC, a malformed sequence, or any other
+// control character is still rejected. Result.ContainsANSI reports whether any were seen.
+func WithRecognizeANSIEscapes() Option {
+	return func(o *Options) {
+		o.RecognizeANSIEscapes = true
+	}
+}
+
+// heuristicMaxInvalidBytes is the number of invalid UTF-8 bytes tolerated in heuristic mode.
+const heuristicMaxInvalidBytes = 8
+
+// heuristicPrintableThreshold is the printable ratio required in heuristic mode.
+const heuristicPrintableThreshold = 0.85
+
+// WithPrintableThreshold counts disallowed characters, such as stray control characters,
+// against the content instead of rejecting it outright. The content is plaintext if the ratio
+// of printable runes to all examined runes and invalid bytes is at least ratio.
+// Invalid UTF-8 bytes are still limited by WithMaxInvalidBytes.
+func WithPrintableThreshold(ratio float64) Option {
+	return func(o *Options) {
+		o.PrintableThreshold = ratio
+	}
+}
+
+// WithNULFastReject rejects content at the first NUL byte, which almost always indicates binary
+// data, even when other disallowed characters are tolerated by a printable threshold.
+func WithNULFastReject() Option {
+	return func(o *Options) {
+		o.NULFastReject = true
+	}
+}
+
+// WithHeuristicMode approximates how file(1) and text editors judge whether content is readable,
+// rather than validating it strictly. It is exactly the combination of:
+//
+//   - WithNULFastReject(): any NUL byte rejects the content.
+//   - WithPrintableThreshold(0.85): other disallowed characters are tolerated while at least
+//     85% of the examined runes and invalid bytes are printable.
+//   - WithMaxInvalidBytes(8): up to 8 bytes that are not valid UTF-8 are tolerated.
+func WithHeuristicMode() Option {
+	return func(o *Options) {
+		WithNULFastReject()(o)
+		WithPrintableThreshold(heuristicPrintableThreshold)(o)
+		WithMaxInvalidBytes(heuristicMaxInvalidBytes)(o)
+	}
+}
+
+// WithTimeBudget limits how long a check spends on the content. The elapsed time is checked
+// after each read, and once the budget is exceeded the verdict for the content read so far is
+// returned with Result.TimedOut and Result.Incomplete set rather than an error. A read that
+// blocks is not interrupted, so the budget can be overrun by the duration of one read.
+func WithTimeBudget(d time.Duration) Option {
+	return func(o *Options) {
+		o.TimeBudget = d
+	}
+}
+
+// WithFollowSymlinks controls whether WalkDir follows symbolic links. It defaults to false, in
+// which case symlinks are skipped. When following, each directory is walked at most once, so a
+// symlink pointing to an ancestor directory does not cause an endless walk.
+func WithFollowSymlinks(follow bool) Option {
+	return func(o *Options) {
+		o.FollowSymlinks = follow
+	}
+}
+
+// WithSnippet captures the first n runes of the content in Result.Snippet, for showing a preview
+// of classified files. The snippet is collected during the same scan and is only reported when
+// the content is plaintext. ANSI escape sequences accepted by WithRecognizeANSIEscapes are left out.
+func WithSnippet(n int) Option {
+	return func(o *Options) {
+		o.SnippetRunes = n
+	}
+}
+
+// WithControlReplacements sets the bytes Sanitize replaces specific control characters with, such
+// as four spaces for a tab or nothing for a carriage return. Mapped characters are replaced even if
+// they are allowed in plaintext. Disallowed control characters that are not mapped are replaced with
+// U+FFFD like any other content that is not plaintext.
+func WithControlReplacements(replacements map[byte][]byte) Option {
+	return func(o *Options) {
+		o.ControlReplacements = replacements
+	}
+}
+
+// WithMinPrintableRatio rejects content in which the fraction of decoded runes that are graphic
+// characters or whitespace is below ratio, even if it is valid UTF-8 without control characters.
+// Random binary occasionally decodes as valid UTF-8, but then mostly as unassigned or private use
+// code points that real text rarely contains. Unlike WithPrintableThreshold, this never accepts
+// content that would otherwise be rejected.
+func WithMinPrintableRatio(ratio float64) Option {
+	return func(o *Options) {
+		o.MinPrintableRatio = ratio
+	}
+}
+
+// WithStopWhenTextBytes stops reading once at least n bytes have been scanned without any issue:
+// no invalid bytes, no tolerated disallowed or non-graphic runes, and no escape sequence in
+// progress. The content is then reported as plaintext with Result.EarlyAccepted and
+// Result.Incomplete set, trading a verdict on the whole content for speed on large text files.
+func WithStopWhenTextBytes(n int64) Option {
+	return func(o *Options) {
+		o.StopWhenTextBytes = n
+	}
+}
+
+// WithUnicodeLineSeparators treats the Unicode line separators U+0085 (NEL), U+2028 (LINE
+// SEPARATOR), and U+2029 (PARAGRAPH SEPARATOR) as line breaks. They are allowed even when
+// WithUnicodeCategory would reject them, and content ending in one has Result.FinalNewline set.
+func WithUnicodeLineSeparators() Option {
+	return func(o *Options) {
+		o.UnicodeLineSeparators = true
+	}
+}
+
+// maxTrailingWhitespaceLines bounds how many line numbers WithTrackTrailingWhitespace reports.
+const maxTrailingWhitespaceLines = 1000
+
+// WithTrackTrailingWhitespace reports the lines that end in spaces or tabs, as flagged by code
+// linters, in Result.TrailingWhitespaceLines and Result.TrailingWhitespaceCount. They are found
+// during the same scan. Only the first 1000 line numbers are listed, so pathological content
+// cannot cause huge allocations; the count covers every such line.
+func WithTrackTrailingWhitespace() Option {
+	return func(o *Options) {
+		o.TrackTrailingWhitespace = true
+	}
+}
+
+// WithSingleReadDecision decides from exactly one Read of the reader into the read buffer and
+// never reads again, for latency-critical sniffing such as non-blocking sockets. A short read
+// reduces the sample, down to nothing if the Read returns no bytes; unless the Read returned
+// io.EOF along with the content, Result.Incomplete is set. Encoding detection reads its own sample first,
+// which may take more than one Read.
+func WithSingleReadDecision() Option {
+	return func(o *Options) {
+		o.SingleReadDecision = true
+	}
+}
+
+// ScanStats describes the progress of a scan for a stop condition.
+type ScanStats struct {
+	// BytesRead is the number of bytes read, after any decoding.
+	BytesRead int64
+
+	// RunesRead is the number of runes validated.
+	RunesRead int64
+
+	// Lines is the number of line breaks seen.
+	Lines int
+}
+
+// WithStopCondition stops the scan once stop returns true, such as after 1000 lines or 1MB,
+// whichever comes first. It generalizes the byte limit of the preview functions to any limit on
+// bytes, runes, and lines. The condition is evaluated after each read, so the scan can run up to
+// one read buffer past the point where it first holds. The verdict covers the content read so
+// far and Result.Incomplete is set.
+func WithStopCondition(stop func(stats ScanStats) bool) Option {
+	return func(o *Options) {
+		o.StopCondition = stop
+	}
+}
+
+// WithForceText classifies files whose path matches one of the glob patterns as plaintext without
+// reading them, like the text attribute in .gitattributes. A pattern is matched against as many
+// trailing elements of the path as it has, so "*.txt" matches the file name in any directory and
+// "docs/*.txt" also requires the parent directory to be named docs. It applies to checks of files
+// by path, such as File and WalkDir, and Result.Reason is set to ForcedText. WithForceBinary takes
+// precedence when both match.
+func WithForceText(patterns []string) Option {
+	return func(o *Options) {
+		o.ForceText = append(o.ForceText, patterns...)
+	}
+}
+
+// WithForceBinary classifies files whose path matches one of the glob patterns as not plaintext
+// without reading them, like the binary attribute in .gitattributes, such as "*.min.js" for
+// minified scripts. Patterns are matched as for WithForceText, and Result.Reason is set to
+// ForcedBinary.
+func WithForceBinary(patterns []string) Option {
+	return func(o *Options) {
+		o.ForceBinary = append(o.ForceBinary, patterns...)
+	}
+}
+
+// WithDetectIndentation reports how the lines of the content are indented, tabs or spaces, in
+// Result.IndentStyle, computed from the leading whitespace of each line during the same scan.
+// For spaces, Result.IndentWidth is the most common change in indentation between lines.
+func WithDetectIndentation() Option {
+	return func(o *Options) {
+		o.DetectIndentation = true
+	}
+}
+
+// WithHardFailAfter rejects content as soon as invalidCount disallowed runes and invalid bytes
+// have been seen, without reading further. Unlike WithPrintableThreshold, WithMaxInvalidBytes,
+// and WithTrailingToleranceBytes, which forgive some non-text content and so keep scanning, it
+// bounds the work spent on maliciously crafted content.
+func WithHardFailAfter(invalidCount int) Option {
+	return func(o *Options) {
+		o.HardFailAfter = invalidCount
+	}
+}
+
+// WithProgress calls progress with the total number of bytes read so far after each read of the
+// content, so a progress bar can be updated while large files are scanned. It is purely
+// observational and does not affect the verdict. The callback runs on the scanning goroutine, so
+// it should return quickly.
+func WithProgress(progress func(bytesRead int64)) Option {
+	return func(o *Options) {
+		o.Progress = progress
+	}
+}
+
+// WithRejectAstral rejects content containing runes beyond U+FFFF, outside the Basic Multilingual
+// Plane, such as emoji and historic scripts. Some identifier rules and legacy systems that store
+// text as UCS-2 disallow them.
+func WithRejectAstral() Option {
+	return func(o *Options) {
+		o.RejectAstral = true
+	}
+}
+
+// WithDetectScript reports the dominant Unicode script among the letters of the content, such as
+// "Latin", "Han", or "Cyrillic", in Result.Script, as a coarse hint for routing content by
+// language. It is computed during the same scan from the first 4096 letters and is best effort:
+// mixed-script content reports whichever script has the most letters.
+func WithDetectScript() Option {
+	return func(o *Options) {
+		o.DetectScript = true
+	}
+}
+
+// WithASCIIFastPath validates content with a tight loop over its bytes, applying the control
+// character policy inline, for as long as they are all ASCII. From the first byte of 0x80 or above
+// the content is decoded as UTF-8 rune by rune as usual, so pure ASCII content is never decoded.
+// The fast path is not taken while options that examine every rune are in effect: ANSI escape
+// recognition, detection of indentation, structured indentation, script, delimiters, or template
+// markers, a minimum printable run, a control density window, and a snippet that is not yet full.
+func WithASCIIFastPath() Option {
+	return func(o *Options) {
+		o.ASCIIFastPath = true
+	}
+}
+
+// WithRequireNFC checks that the text is in Unicode Normalization Form C, as expected by systems
+// that store normalized text, and sets Result.NotNormalized when it is not, for example when an é
+// is written as an e followed by a combining acute accent. The content is still plaintext; use
+// WithRejectUnnormalized to reject it instead.
+func WithRequireNFC() Option {
+	return func(o *Options) {
+		o.RequireNFC = true
+	}
+}
+
+// WithRejectUnnormalized rejects text that is not in Unicode Normalization Form C, as checked by
+// WithRequireNFC, which it implies.
+func WithRejectUnnormalized() Option {
+	return func(o *Options) {
+		o.RequireNFC = true
+		o.RejectUnnormalized = true
+	}
+}
+
+// WithDetectDelimited recognizes delimited data such as CSV and TSV. When the content is
+// plaintext and every non-empty line has the same number of commas, tabs, or pipes outside
+// double-quoted fields, Result.Delimited is set with the delimiter and the number of columns.
+// At least two lines are needed. This is a heuristic: quoting is only handled to the extent of
+// ignoring delimiters and line breaks between double quotes.
+func WithDetectDelimited() Option {
+	return func(o *Options) {
+		o.DetectDelimited = true
+	}
+}
+
+// WithMaxDecompressedBytes limits FileAutoDecompress to decompressing at most n bytes. If the
+// check needs to read further, it fails with ErrDecompressionLimitExceeded, protecting against
+// decompression bombs: small files that expand enormously. A check that reaches a verdict within
+// the limit, such as on binary content, is not affected. Uncompressed files are not limited.
+func WithMaxDecompressedBytes(n int64) Option {
+	return func(o *Options) {
+		o.MaxDecompressedBytes = n
+	}
+}
+
+// WithMinPrintableRun requires the content to contain at least one run of n consecutive printable
+// runes, graphic characters or whitespace, to be plaintext. Binary data that happens to be valid
+// UTF-8 decodes to printable runes scattered between unassigned and control code points, while
+// real text has long runs of them. Content that is entirely printable is accepted even if it is
+// shorter than n runes.
+func WithMinPrintableRun(n int) Option {
+	return func(o *Options) {
+		o.MinPrintableRun = int64(n)
+	}
+}
+
+// WithPreviewKB checks only the first maxKB kilobytes of the content, like FilePreview and
+// ReaderPreview do, for functions that take options. Zero or a negative maxKB checks everything.
+func WithPreviewKB(maxKB int) Option {
+	return func(o *Options) {
+		o.PreviewKB = maxKB
+	}
+}
+
+// WithMaxConsecutiveInvalid rejects content with n invalid UTF-8 bytes or U+FFFD replacement
+// characters in a row, even if the total is within WithMaxInvalidBytes. Decoders substitute
+// replacement characters for bytes that are invalid in the encoding, so a burst of them means the
+// content is binary or the encoding is wrong, while the occasional bad byte in text is tolerated.
+func WithMaxConsecutiveInvalid(n int) Option {
+	return func(o *Options) {
+		o.MaxConsecutiveInvalid = n
+	}
+}
+
+// WithDetectByteWidth reports in Result.MaxByteWidth whether all of the text is 7-bit ASCII, is
+// within the 8-bit Latin-1 range, or needs a multibyte encoding, such as to choose between a
+// VARCHAR and an NVARCHAR column. The width is of the decoded characters, not of their encoding:
+// "café" is EightBit although the é takes two bytes in UTF-8.
+func WithDetectByteWidth() Option {
+	return func(o *Options) {
+		o.DetectByteWidth = true
+	}
+}
+
+// WithDetectTemplateMarkers sets Result.ContainsTemplateMarkers when plaintext contains
+// interpolation markers that were not rendered: "{{" followed later by "}}", as in Go, Jinja, and
+// Mustache templates, or "${" followed later by "}", as in shell and Terraform. This is metadata
+// only and does not affect the verdict. Shell scripts using "${VAR}" are reported too.
+func WithDetectTemplateMarkers() Option {
+	return func(o *Options) {
+		o.DetectTemplateMarkers = true
+	}
+}
+
+// WithControlDensityWindow tolerates control characters as long as they are sparse: content is
+// only rejected when, in some window of windowBytes consecutive bytes, more than maxRatio of the
+// bytes are control characters. This suits formats such as terminal recordings, where bursts of
+// control characters are legitimate, while binary content is dense with them throughout. Content
+// shorter than the window is judged as a whole. Other disallowed runes are rejected as usual.
+func WithControlDensityWindow(windowBytes int, maxRatio float64) Option {
+	return func(o *Options) {
+		o.ControlDensityWindow = windowBytes
+		o.ControlDensityMaxRatio = maxRatio
+	}
+}
+
+// WithDetectStructuredIndent sets Result.StructuredIndentLikely for plaintext that looks like
+// YAML or Python: indented with spaces that change in consistent steps of 2 or 4, with at least
+// a quarter of its lines being "key: value" pairs or ending in a colon. This is a coarse hint
+// for classifiers and does not affect the verdict.
+func WithDetectStructuredIndent() Option {
+	return func(o *Options) {
+		o.DetectStructuredIndent = true
+	}
+}
+
+// WithMaxOpenFiles limits how many files are open at once, across every check given this same
+// option value, to n. The semaphore belongs to the option, so build it once and share it between
+// goroutines that run CheckFile, WalkDir, or FileAutoDecompress concurrently over a large tree;
+// checks beyond the limit wait for a file to be closed instead of failing with "too many open
+// files". A value of 0 or less leaves the number of open files unlimited.
+func WithMaxOpenFiles(n int) Option {
+	var sem chan struct{}
+	if n > 0 {
+		sem = make(chan struct{}, n)
+	}
+	return func(o *Options) {
+		o.MaxOpenFiles = n
+		o.openFiles = sem
+	}
+}
+
+// WithRejectFormatChars rejects content containing characters in Unicode category Cf, such as the
+// soft hyphen, zero-width space and joiners, and bidirectional controls. They are accepted by
+// default because they are not control characters, but they are invisible and often unwanted in
+// clean text such as identifiers or configuration. A byte order mark at the very start is still
+// accepted. The first offending character is reported in Result.RejectedFormatChar.
+func WithRejectFormatChars() Option {
+	return func(o *Options) {
+		o.RejectFormatChars = true
+	}
+}
+
+// WithCountWords sets Result.Words to the number of words in plaintext, counted in the same scan
+// as the classification. A word is a run of runes that are not Unicode whitespace, so words are
+// split by spaces, tabs, line breaks, and other whitespace such as U+00A0 NO-BREAK SPACE and
+// U+3000 IDEOGRAPHIC SPACE. Scripts written without spaces, such as Chinese, count a whole run as
+// one word. This is metadata only and does not affect the verdict.
+func WithCountWords() Option {
+	return func(o *Options) {
+		o.CountWords = true
+	}
+}
+
+// WithIdleTimeout treats the content as ended when the reader delivers no bytes for d, returning
+// the verdict for the bytes received so far with Result.Incomplete set. This guards against
+// readers that stop sending without ever returning io.EOF, such as half-closed connections and
+// some pipes. Each read waits in a separate goroutine; one abandoned by the timeout stays blocked
+// until the reader returns, so close the reader afterwards to release it.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.IdleTimeout = d
+	}
+}
+
+// WithNormalizeForMetrics makes the line-based metrics, such as indentation, structured indent,
+// and delimited data detection, treat CRLF, lone CR, and lone LF alike as single line breaks, so
+// content with mixed line endings is measured the same as content with uniform ones. The raw
+// line endings are reported separately in Result.LineEndings. Result.Lines always counts each
+// kind of line break once. This does not affect the verdict.
+func WithNormalizeForMetrics() Option {
+	return func(o *Options) {
+		o.NormalizeForMetrics = true
+	}
+}
@@ -0,0 +1,1104 @@
+package isplaintextfile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// failingReader returns its content and then fails with err instead of io.EOF.
+type failingReader struct {
+	content []byte
+	err     error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.content) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.content)
+	r.content = r.content[n:]
+	return n, nil
+}
+
+func TestWithBestEffortOnError(t *testing.T) {
+	errBroken := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"text then error", []byte("Hello, World!\n"), true},
+		{"binary then error", []byte{'H', 'i', 0x00}, false},
+		{"split rune then error", []byte("Hello \xf0\x9f"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Without the option the read error is returned.
+			_, err := Check(&failingReader{content: tt.content, err: errBroken})
+			if tt.expected && !errors.Is(err, errBroken) {
+				t.Errorf("Check() error = %v, want %v", err, errBroken)
+			}
+
+			res, err := Check(&failingReader{content: tt.content, err: errBroken}, WithBestEffortOnError())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if tt.expected && !res.Incomplete {
+				t.Errorf("Check().Incomplete = %v, want true", res.Incomplete)
+			}
+		})
+	}
+
+	// A clean EOF is never reported as incomplete.
+	res, err := Check(&failingReader{content: []byte("Hello"), err: io.EOF}, WithBestEffortOnError())
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.IsPlaintext || res.Incomplete {
+		t.Errorf("Check() = %+v, want plaintext and complete", res)
+	}
+
+	// An error before any content is read is returned.
+	if res, err := Check(&failingReader{err: errBroken}, WithBestEffortOnError()); !errors.Is(err, errBroken) || res.IsPlaintext {
+		t.Errorf("Check() = %+v, %v, want %v", res, err, errBroken)
+	}
+	if _, err := CheckFile(t.TempDir(), WithBestEffortOnError()); err == nil {
+		t.Error("CheckFile() of a directory error = nil, want error")
+	}
+}
+
+func TestWithUnicodeCategory(t *testing.T) {
+	opt := WithUnicodeCategory(unicode.L, unicode.N, unicode.White_Space)
+
+	tests := []struct {
+		name       string
+		content    string
+		defaultRes bool
+		strictRes  bool
+	}{
+		{"letters and digits", "user42 名前\n", true, true},
+		{"private use code point", "user\uE000", true, false},
+		{"punctuation", "hello, world", true, false},
+		{"control character", "user\x07", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.defaultRes {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.defaultRes)
+			}
+
+			res, err = Check(strings.NewReader(tt.content), opt)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.strictRes {
+				t.Errorf("Check(WithUnicodeCategory).IsPlaintext = %v, want %v", res.IsPlaintext, tt.strictRes)
+			}
+		})
+	}
+}
+
+func TestWithRejectInteriorBOM(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		defaultRes bool
+		strictRes  bool
+	}{
+		{"no BOM", "first file\nsecond file\n", true, true},
+		{"leading BOM", "\uFEFFfirst file\n", true, true},
+		{"interior BOM", "first file\n\uFEFFsecond file\n", true, false},
+		{"leading and interior BOM", "\uFEFFfirst file\n\uFEFFsecond file\n", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.defaultRes {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.defaultRes)
+			}
+
+			res, err = Check(strings.NewReader(tt.content), WithRejectInteriorBOM())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.strictRes {
+				t.Errorf("Check(WithRejectInteriorBOM).IsPlaintext = %v, want %v", res.IsPlaintext, tt.strictRes)
+			}
+		})
+	}
+}
+
+func TestWithAllowedControlChars(t *testing.T) {
+	content := "page one\fpage two\n"
+
+	res, err := Check(strings.NewReader(content))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if res.IsPlaintext {
+		t.Errorf("Check().IsPlaintext = %v, want false", res.IsPlaintext)
+	}
+
+	res, err = Check(strings.NewReader(content), WithAllowedControlChars('\f'))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.IsPlaintext {
+		t.Errorf("Check(WithAllowedControlChars).IsPlaintext = %v, want true", res.IsPlaintext)
+	}
+
+	// Only the listed control characters are allowed.
+	res, err = Check(strings.NewReader(content+"\x07"), WithAllowedControlChars('\f'))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if res.IsPlaintext {
+		t.Errorf("Check(WithAllowedControlChars).IsPlaintext = %v, want false", res.IsPlaintext)
+	}
+}
+
+func TestSetDefaultOptions(t *testing.T) {
+	defer SetDefaultOptions()
+
+	content := []byte("page one\fpage two\n")
+
+	if res, _ := Bytes(content); res {
+		t.Errorf("Bytes() = %v, want false", res)
+	}
+
+	SetDefaultOptions(WithAllowedControlChars('\f'))
+	if res, _ := Bytes(content); !res {
+		t.Errorf("Bytes() with default options = %v, want true", res)
+	}
+	if res, _ := Reader(strings.NewReader(string(content))); !res {
+		t.Errorf("Reader() with default options = %v, want true", res)
+	}
+
+	// Defaults that reject ASCII text apply to the in-memory functions too.
+	SetDefaultOptions(WithUnicodeCategory(unicode.L))
+	if res, _ := Bytes([]byte("hello world")); res {
+		t.Errorf("Bytes() with letters only = %v, want false", res)
+	}
+	if MustString("hello world") {
+		t.Error("MustString() with letters only = true, want false")
+	}
+
+	SetDefaultOptions()
+	if res, _ := Bytes(content); res {
+		t.Errorf("Bytes() after reset = %v, want false", res)
+	}
+}
+
+func TestInMemoryAllocations(t *testing.T) {
+	data := []byte("hello world\n")
+	multibyte := []byte("h\u00e9llo w\u00f6rld\n")
+
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"Bytes", func() { _, _ = Bytes(data) }},
+		{"Bytes multibyte", func() { _, _ = Bytes(multibyte) }},
+		{"MustBytes", func() { MustBytes(data) }},
+		{"MustString", func() { MustString("hello world\n") }},
+		{"BytesDetailed", func() { _, _ = BytesDetailed(data) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.f); allocs != 0 {
+				t.Errorf("%s allocations = %v, want 0", tt.name, allocs)
+			}
+		})
+	}
+}
+
+func TestWithMaxFileSize(t *testing.T) {
+	dir := t.TempDir()
+
+	// A sparse file occupies no disk space but reports a large size.
+	sparse := filepath.Join(dir, "sparse.bin")
+	f, err := os.Create(sparse)
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	if err := f.Truncate(1 << 30); err != nil {
+		t.Fatalf("Failed to truncate temp file: %v", err)
+	}
+	f.Close()
+
+	// A text file that would pass if it were read.
+	text := filepath.Join(dir, "text.txt")
+	if err := os.WriteFile(text, bytes.Repeat([]byte("plain text\n"), 200), 0o600); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+
+	for _, path := range []string{sparse, text} {
+		res, err := CheckFile(path, WithMaxFileSize(1024))
+		if err != nil {
+			t.Errorf("CheckFile(%s) error: %v", path, err)
+		}
+		if res.IsPlaintext || res.Reason != SkippedTooLarge {
+			t.Errorf("CheckFile(%s) = %+v, want skipped as too large", path, res)
+		}
+	}
+
+	res, err := CheckFile(text, WithMaxFileSize(1<<20))
+	if err != nil {
+		t.Errorf("CheckFile() error: %v", err)
+	}
+	if !res.IsPlaintext || res.Reason != "" {
+		t.Errorf("CheckFile() = %+v, want plaintext", res)
+	}
+}
+
+func TestWithMaxInvalidBytes(t *testing.T) {
+	content := "a truncated paste \xe2\x82 with two invalid bytes\n"
+
+	tests := []struct {
+		name      string
+		tolerance int
+		expected  bool
+	}{
+		{"no tolerance", 0, false},
+		{"tolerance below count", 1, false},
+		{"tolerance equal to count", 2, true},
+		{"tolerance above count", 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(content), WithMaxInvalidBytes(tt.tolerance))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if tt.expected && res.InvalidBytes != 2 {
+				t.Errorf("Check().InvalidBytes = %d, want 2", res.InvalidBytes)
+			}
+		})
+	}
+}
+
+func TestWithTrailingToleranceBytes(t *testing.T) {
+	text := strings.Repeat("valid text content\n", 100)
+	trailer := "\x00\x9f\xff\x01\xfe\x07\x80\x02"
+
+	tests := []struct {
+		name      string
+		content   string
+		tolerance int
+		expected  bool
+		ignored   int64
+	}{
+		{"trailer within tolerance", text + trailer, 16, true, 8},
+		{"trailer at tolerance", text + trailer, 8, true, 8},
+		{"trailer beyond tolerance", text + trailer, 4, false, 0},
+		{"no tolerance", text + trailer, 0, false, 0},
+		{"binary before the end", trailer + text, 16, false, 0},
+		{"truncated rune at end", text + "\xe4\xbd", 16, true, 2},
+		{"clean text", text, 16, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A small read buffer spreads the trailer across several chunks.
+			for _, window := range []int{3, defaultBufferSize} {
+				s := &scanner{opts: buildOptions([]Option{WithTrailingToleranceBytes(tt.tolerance)})}
+				res, err := scanReader(strings.NewReader(tt.content), make([]byte, window), s)
+				if err != nil {
+					t.Errorf("scanReader() error: %v", err)
+				}
+				if res.IsPlaintext != tt.expected {
+					t.Errorf("window %d: IsPlaintext = %v, want %v", window, res.IsPlaintext, tt.expected)
+				}
+				if res.TrailingIgnored != tt.ignored {
+					t.Errorf("window %d: TrailingIgnored = %d, want %d", window, res.TrailingIgnored, tt.ignored)
+				}
+			}
+		})
+	}
+}
+
+func TestWithHeuristicMode(t *testing.T) {
+	prose := strings.Repeat("The quick brown fox jumps over the lazy dog.\n", 4)
+
+	tests := []struct {
+		name      string
+		content   string
+		strict    bool
+		heuristic bool
+	}{
+		{"clean text", prose, true, true},
+		{"stray form feeds and bell", prose + "\f\a" + prose, false, true},
+		{"a few invalid bytes", prose + "\xff\xfe" + prose, false, true},
+		{"NUL byte", prose + "\x00" + prose, false, false},
+		{"mostly control characters", strings.Repeat("a\x01\x02", 50), false, false},
+		{"many invalid bytes", prose + strings.Repeat("\xff", 9), false, false},
+		{"empty", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.strict {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.strict)
+			}
+
+			res, err = Check(strings.NewReader(tt.content), WithHeuristicMode())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.heuristic {
+				t.Errorf("Check(WithHeuristicMode).IsPlaintext = %v, want %v", res.IsPlaintext, tt.heuristic)
+			}
+		})
+	}
+}
+
+func TestWithPrintableThreshold(t *testing.T) {
+	// 9 printable runes and 1 control character.
+	content := "abcdefghi\x01"
+
+	for _, tt := range []struct {
+		threshold float64
+		expected  bool
+	}{
+		{0.85, true},
+		{0.9, true},
+		{0.95, false},
+	} {
+		res, err := Check(strings.NewReader(content), WithPrintableThreshold(tt.threshold))
+		if err != nil {
+			t.Errorf("Check() error: %v", err)
+		}
+		if res.IsPlaintext != tt.expected {
+			t.Errorf("Check(WithPrintableThreshold(%v)).IsPlaintext = %v, want %v", tt.threshold, res.IsPlaintext, tt.expected)
+		}
+		if res.PrintableRatio != 0.9 {
+			t.Errorf("Check().PrintableRatio = %v, want 0.9", res.PrintableRatio)
+		}
+	}
+}
+
+// slowReader delivers one byte of its content per read after a delay.
+type slowReader struct {
+	content []byte
+	delay   time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.content) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := copy(p[:1], r.content)
+	r.content = r.content[n:]
+	return n, nil
+}
+
+func TestWithTimeBudget(t *testing.T) {
+	content := []byte(strings.Repeat("slow text\n", 100))
+
+	res, err := Check(&slowReader{content: content, delay: time.Millisecond}, WithTimeBudget(5*time.Millisecond))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.TimedOut || !res.Incomplete {
+		t.Errorf("Check() = %+v, want timed out", res)
+	}
+	if !res.IsPlaintext {
+		t.Errorf("Check().IsPlaintext = %v, want true", res.IsPlaintext)
+	}
+	if res.RuneCount == 0 || res.RuneCount >= int64(len(content)) {
+		t.Errorf("Check().RuneCount = %d, want a partial count", res.RuneCount)
+	}
+
+	// A generous budget completes normally.
+	res, err = Check(strings.NewReader(string(content)), WithTimeBudget(time.Minute))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if res.TimedOut || res.Incomplete || !res.IsPlaintext {
+		t.Errorf("Check() = %+v, want complete plaintext", res)
+	}
+}
+
+func TestWithMinPrintableRatio(t *testing.T) {
+	// Random runes from planes 4 to 13, which have no assigned characters, make a blob that is
+	// valid UTF-8 without control characters.
+	rng := rand.New(rand.NewPCG(1, 2))
+	var blob []byte
+	for range 256 {
+		blob = utf8.AppendRune(blob, rune(0x40000+rng.IntN(0xA0000)))
+	}
+	if !utf8.Valid(blob) || !MustBytes(blob) {
+		t.Fatalf("blob is not valid UTF-8 plaintext")
+	}
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"unassigned code points", blob, false},
+		{"plain text", []byte("Hello, World!\n"), true},
+		{"multibyte text", []byte("你好，世界！\n"), true},
+		{"mostly text", append([]byte(strings.Repeat("text ", 10)), "\U00050000"...), true},
+		{"empty", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(bytes.NewReader(tt.content), WithMinPrintableRatio(0.9))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+		})
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func TestWithStopWhenTextBytes(t *testing.T) {
+	const threshold = 64 * 1024
+	text := &repeatReader{pattern: []byte("clean text\n"), n: 10 * 1024 * 1024}
+	counter := &countingReader{r: text}
+
+	res, err := check(counter, make([]byte, 4096), buildOptions([]Option{WithStopWhenTextBytes(threshold)}))
+	if err != nil {
+		t.Fatalf("check() error: %v", err)
+	}
+	if !res.IsPlaintext || !res.EarlyAccepted || !res.Incomplete {
+		t.Errorf("check() = %+v, want early accepted plaintext", res)
+	}
+	if counter.n < threshold || counter.n >= threshold+4096 {
+		t.Errorf("read %d bytes, want to stop just after %d", counter.n, threshold)
+	}
+
+	// A tolerated invalid byte is an issue, so the content is read to the end.
+	content := append([]byte("caf\xe9\n"), strings.Repeat("clean text\n", 100)...)
+	res, err = check(bytes.NewReader(content), make([]byte, 16), buildOptions([]Option{WithStopWhenTextBytes(32), WithMaxInvalidBytes(1)}))
+	if err != nil {
+		t.Fatalf("check() error: %v", err)
+	}
+	if res.EarlyAccepted || res.Incomplete || !res.IsPlaintext {
+		t.Errorf("check() = %+v, want complete plaintext", res)
+	}
+
+	// Content shorter than the threshold is not accepted early.
+	res, err = Check(strings.NewReader("short\n"), WithStopWhenTextBytes(threshold))
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if res.EarlyAccepted || res.Incomplete {
+		t.Errorf("Check() = %+v, want complete scan", res)
+	}
+}
+
+func TestWithUnicodeLineSeparators(t *testing.T) {
+	letters := WithUnicodeCategory(unicode.L, unicode.Zs)
+
+	tests := []struct {
+		name                 string
+		content              string
+		opts                 []Option
+		expected             bool
+		expectedFinalNewline bool
+	}{
+		{"line separator only", "line one\u2028line two\u2028", []Option{WithUnicodeLineSeparators()}, true, true},
+		{"line separator by default", "line one\u2028line two\u2028", nil, true, false},
+		{"paragraph separator", "one\u2029two\u2029", []Option{WithUnicodeLineSeparators()}, true, true},
+		{"NEL", "one\u0085two\u0085", []Option{WithUnicodeLineSeparators()}, true, true},
+		{"no final separator", "one\u2028two", []Option{WithUnicodeLineSeparators()}, true, false},
+		{"restricted categories", "one\u2028two\u2028", []Option{letters}, false, false},
+		{"restricted categories with separators", "one\u2028two\u2028", []Option{letters, WithUnicodeLineSeparators()}, true, true},
+		{"invalid byte after separator", "one\u2028\xff", []Option{WithUnicodeLineSeparators(), WithMaxInvalidBytes(1)}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), tt.opts...)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.IsPlaintext && res.FinalNewline != tt.expectedFinalNewline {
+				t.Errorf("Check().FinalNewline = %v, want %v", res.FinalNewline, tt.expectedFinalNewline)
+			}
+		})
+	}
+
+	// Separators alone are whitespace.
+	res, err := Check(strings.NewReader("\u2028\u2029\u0085"), WithUnicodeLineSeparators())
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.WhitespaceOnly {
+		t.Errorf("Check().WhitespaceOnly = %v, want true", res.WhitespaceOnly)
+	}
+}
+
+func TestWithTrackTrailingWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []int
+	}{
+		{"two of three lines", "first line  \nsecond line\nthird line\t\n", []int{1, 3}},
+		{"CRLF", "a \r\nb\r\nc\t\r\n", []int{1, 3}},
+		{"final partial line", "a\nb ", []int{2}},
+		{"blank line", "a\n\nb\n", nil},
+		{"whitespace only line", "a\n  \nb\n", []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithTrackTrailingWhitespace())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if !slices.Equal(res.TrailingWhitespaceLines, tt.expected) {
+				t.Errorf("Check().TrailingWhitespaceLines = %v, want %v", res.TrailingWhitespaceLines, tt.expected)
+			}
+			if res.TrailingWhitespaceCount != len(tt.expected) {
+				t.Errorf("Check().TrailingWhitespaceCount = %d, want %d", res.TrailingWhitespaceCount, len(tt.expected))
+			}
+		})
+	}
+
+	// The list is bounded but the count is not.
+	res, err := Check(strings.NewReader(strings.Repeat("x \n", 5000)), WithTrackTrailingWhitespace())
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if len(res.TrailingWhitespaceLines) != maxTrailingWhitespaceLines || res.TrailingWhitespaceCount != 5000 {
+		t.Errorf("Check() listed %d lines and counted %d, want %d and 5000",
+			len(res.TrailingWhitespaceLines), res.TrailingWhitespaceCount, maxTrailingWhitespaceLines)
+	}
+
+	// Without the option nothing is tracked.
+	if res, _ := Check(strings.NewReader("a \n")); res.TrailingWhitespaceLines != nil || res.TrailingWhitespaceCount != 0 {
+		t.Errorf("Check() = %+v, want no trailing whitespace tracked", res)
+	}
+}
+
+// chunkReader returns one chunk per Read and counts the calls.
+type chunkReader struct {
+	chunks [][]byte
+	reads  int
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	c.reads++
+	if len(c.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, c.chunks[0])
+	c.chunks = c.chunks[1:]
+	return n, nil
+}
+
+func TestWithSingleReadDecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunks   [][]byte
+		expected bool
+	}{
+		{"small text chunk before binary", [][]byte{[]byte("hi"), {0x00, 0x01, 0x02}}, true},
+		{"binary first chunk", [][]byte{{0x00, 0x01}, []byte("text")}, false},
+		{"rune split after the first chunk", [][]byte{[]byte("caf\xc3"), []byte("\xa9")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &chunkReader{chunks: tt.chunks}
+			res, err := Check(r, WithSingleReadDecision())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if r.reads != 1 {
+				t.Errorf("Check() made %d reads, want 1", r.reads)
+			}
+			if tt.expected && !res.Incomplete {
+				t.Errorf("Check().Incomplete = %v, want true", res.Incomplete)
+			}
+		})
+	}
+
+	// Content returned in full with io.EOF is complete.
+	res, err := Check(iotest.DataErrReader(strings.NewReader("all of it\n")), WithSingleReadDecision())
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.IsPlaintext || res.Incomplete {
+		t.Errorf("Check() = %+v, want complete plaintext", res)
+	}
+}
+
+func TestOptionConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected *OptionError
+	}{
+		{"two encodings", []Option{WithEncoding(Latin1), WithEncoding(AutoDetect)},
+			&OptionError{Option: "WithEncoding(ISO-8859-1)", Conflict: "WithEncoding(auto)", Reason: "only one encoding can be used"}},
+		{"NUL allowed and rejected", []Option{WithNULFastReject(), WithAllowedControlChars(0)},
+			&OptionError{Option: "WithNULFastReject()", Conflict: "WithAllowedControlChars(0)", Reason: "NUL cannot be both allowed and rejected"}},
+		{"declared and fixed encoding", []Option{WithRespectDeclaredEncoding(), WithEncoding(Latin1)},
+			&OptionError{Option: "WithRespectDeclaredEncoding()", Conflict: "WithEncoding(ISO-8859-1)", Reason: "a fixed encoding overrides any declared encoding"}},
+		{"same encoding twice", []Option{WithCodePage(37), WithEncoding(EBCDIC)}, nil},
+		{"encoding alias", []Option{WithEncoding("latin1"), WithEncoding(Latin1)}, nil},
+		{"declared with auto-detection", []Option{WithRespectDeclaredEncoding(), WithEncoding(AutoDetect)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Check(strings.NewReader("text\n"), tt.opts...)
+			if tt.expected == nil {
+				if err != nil {
+					t.Errorf("Check() error: %v", err)
+				}
+				return
+			}
+			var optErr *OptionError
+			if !errors.As(err, &optErr) {
+				t.Fatalf("Check() error = %v, want *OptionError", err)
+			}
+			if *optErr != *tt.expected {
+				t.Errorf("Check() error = %+v, want %+v", optErr, tt.expected)
+			}
+		})
+	}
+
+	// A per-call encoding overrides a default one.
+	SetDefaultOptions(WithEncoding(Latin1))
+	defer SetDefaultOptions()
+	if err := buildOptions([]Option{WithEncoding(UTF8)}).Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
+
+func TestOptionConflictsInDefaults(t *testing.T) {
+	SetDefaultOptions(WithNULFastReject(), WithAllowedControlChars(0))
+	defer SetDefaultOptions()
+
+	var optErr *OptionError
+	if res, err := Bytes([]byte("text\n")); res || !errors.As(err, &optErr) {
+		t.Errorf("Bytes() = %v, %v, want *OptionError", res, err)
+	}
+	if MustBytes([]byte("text\n")) {
+		t.Errorf("MustBytes() = true, want false")
+	}
+	if res, err := ByteReader(bufio.NewReader(strings.NewReader("text\n"))); res || !errors.As(err, &optErr) {
+		t.Errorf("ByteReader() = %v, %v, want *OptionError", res, err)
+	}
+	if _, err := Chunks(strings.NewReader("text\n"), 512); !errors.As(err, &optErr) {
+		t.Errorf("Chunks() error = %v, want *OptionError", err)
+	}
+	if _, err := Sanitize([]byte("text\n")); !errors.As(err, &optErr) {
+		t.Errorf("Sanitize() error = %v, want *OptionError", err)
+	}
+}
+
+func TestWithStopCondition(t *testing.T) {
+	lines := [][]byte{[]byte("one\n"), []byte("two\n"), []byte("three\n"), {0x00, 0x01, 0x02}}
+
+	tests := []struct {
+		name     string
+		stop     func(ScanStats) bool
+		expected bool
+		reads    int
+	}{
+		{"stop after three lines", func(s ScanStats) bool { return s.Lines >= 3 }, true, 3},
+		{"stop after 1MB or three lines", func(s ScanStats) bool { return s.BytesRead >= 1<<20 || s.Lines >= 3 }, true, 3},
+		{"stop after eight runes", func(s ScanStats) bool { return s.RunesRead >= 8 }, true, 2},
+		{"never stop", func(ScanStats) bool { return false }, false, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &chunkReader{chunks: lines}
+			res, err := Check(r, WithStopCondition(tt.stop))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if r.reads != tt.reads {
+				t.Errorf("Check() made %d reads, want %d", r.reads, tt.reads)
+			}
+			if tt.expected && !res.Incomplete {
+				t.Errorf("Check().Incomplete = %v, want true", res.Incomplete)
+			}
+		})
+	}
+}
+
+func TestWithHardFailAfter(t *testing.T) {
+	const size = 10 * 1024 * 1024
+	lenient := []Option{WithPrintableThreshold(0.5), WithMaxInvalidBytes(size), WithTrailingToleranceBytes(size)}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		maxRead int64
+	}{
+		{"tolerated to the end", lenient, size},
+		{"hard fail", append(lenient, WithHardFailAfter(16)), 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binary := &repeatReader{pattern: []byte{'a', 0x01, 0x02, 0xff, 0xfe}, n: size}
+			counter := &countingReader{r: binary}
+
+			res, err := check(counter, make([]byte, 4096), buildOptions(tt.opts))
+			if err != nil {
+				t.Fatalf("check() error: %v", err)
+			}
+			if res.IsPlaintext {
+				t.Errorf("check().IsPlaintext = %v, want false", res.IsPlaintext)
+			}
+			if counter.n > tt.maxRead {
+				t.Errorf("read %d bytes, want at most %d", counter.n, tt.maxRead)
+			}
+		})
+	}
+
+	// Below the limit the tolerance still applies.
+	res, err := Check(strings.NewReader("caf\xe9\n"), WithMaxInvalidBytes(2), WithHardFailAfter(2))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if !res.IsPlaintext {
+		t.Errorf("Check().IsPlaintext = %v, want true", res.IsPlaintext)
+	}
+}
+
+func TestWithProgress(t *testing.T) {
+	const size = 1024 * 1024
+	var reported []int64
+	progress := func(bytesRead int64) {
+		reported = append(reported, bytesRead)
+	}
+
+	res, err := Check(&repeatReader{pattern: []byte("progress\n"), n: size}, WithProgress(progress))
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if !res.IsPlaintext {
+		t.Errorf("Check().IsPlaintext = %v, want true", res.IsPlaintext)
+	}
+	if len(reported) < 2 {
+		t.Fatalf("progress called %d times, want several", len(reported))
+	}
+	if last := reported[len(reported)-1]; last != size {
+		t.Errorf("progress last reported %d, want %d", last, size)
+	}
+	for i := 1; i < len(reported); i++ {
+		if reported[i] <= reported[i-1] {
+			t.Errorf("progress reported %d after %d, want increasing counts", reported[i], reported[i-1])
+		}
+	}
+}
+
+func TestWithRejectAstral(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"emoji", "Hello \U0001F44B\n", false},
+		{"historic script", "\U00010300\n", false},
+		{"BMP text", "你好，世界！\n", true},
+		{"last BMP rune", "\uffff\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithRejectAstral())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+
+			// Astral runes are accepted by default.
+			if !MustString(tt.content) {
+				t.Errorf("MustString(%q) = false, want true", tt.content)
+			}
+		})
+	}
+}
+
+func TestWithRejectFormatChars(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+		rejected rune
+	}{
+		{"soft hyphen", "co\u00adoperate\n", false, '\u00ad'},
+		{"zero-width space", "key\u200b = value\n", false, '\u200b'},
+		{"first of several", "a\u200db\u00adc\n", false, '\u200d'},
+		{"leading byte order mark", "\uFEFFtext\n", true, 0},
+		{"interior byte order mark", "text\uFEFF\n", false, '\uFEFF'},
+		{"clean text", "Hello, World!\n", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithRejectFormatChars())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.RejectedFormatChar != tt.rejected {
+				t.Errorf("Check().RejectedFormatChar = %U, want %U", res.RejectedFormatChar, tt.rejected)
+			}
+
+			// Format characters are accepted by default.
+			if !MustString(tt.content) {
+				t.Errorf("MustString(%q) = false, want true", tt.content)
+			}
+		})
+	}
+}
+
+func TestWithMinPrintableRun(t *testing.T) {
+	// Valid UTF-8 with printable runes scattered between C1 control and private use code points.
+	scattered := strings.Repeat("ab\u0080cdef\u0099g\ue000hijkl\u0090", 100)
+
+	tests := []struct {
+		name     string
+		content  string
+		opts     []Option
+		expected bool
+	}{
+		{"text", "The quick brown fox jumps over the lazy dog.\n", nil, true},
+		{"scattered printable runes", scattered, nil, false},
+		{"run of exactly eight", scattered + "abcdefgh", nil, true},
+		{"run broken by an invalid byte", strings.Repeat("abcd\xffefg\u0080", 10), []Option{WithMaxInvalidBytes(100)}, false},
+		{"short text", "hi\n", nil, true},
+		{"short with a control code point", "hi\u0080", nil, false},
+		{"empty", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), append(tt.opts, WithMinPrintableRun(8))...)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+		})
+	}
+
+	// The scattered content passes the default policy.
+	if res, _ := Check(strings.NewReader(scattered)); !res.IsPlaintext {
+		t.Errorf("Check() without WithMinPrintableRun() = %v, want true", res.IsPlaintext)
+	}
+}
+
+func TestWithPreviewKB(t *testing.T) {
+	content := append(bytes.Repeat([]byte("A"), 2048), 0x00)
+
+	tests := []struct {
+		name     string
+		maxKB    int
+		expected bool
+	}{
+		{"one kilobyte", 1, true},
+		{"three kilobytes", 3, false},
+		{"zero checks everything", 0, false},
+		{"NoLimit checks everything", NoLimit, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(bytes.NewReader(content), WithPreviewKB(tt.maxKB))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWithMaxConsecutiveInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		opts     []Option
+		expected bool
+	}{
+		{"scattered invalid bytes", "text \xff more \xfe text \xfd", []Option{WithMaxInvalidBytes(100)}, true},
+		{"cluster below the limit", "text \xff\xfe\xfd text", []Option{WithMaxInvalidBytes(100)}, true},
+		{"cluster at the limit", "text \xff\xfe\xfd\xfc text", []Option{WithMaxInvalidBytes(100)}, false},
+		{"replacement characters", "text \uFFFD\uFFFD\uFFFD\uFFFD text", nil, false},
+		{"invalid bytes and replacement characters", "text \xff\uFFFD\xfe\uFFFD text", []Option{WithMaxInvalidBytes(100)}, false},
+		{"single replacement characters", strings.Repeat("text \uFFFD ", 10), nil, true},
+		// Four unpaired surrogates decode to replacement characters.
+		{"decoded UTF-16 garbage", "h\x00\x00\xd8\x00\xd8\x00\xd8\x00\xd8i\x00", []Option{WithEncoding(UTF16LE)}, false},
+		{"decoded UTF-16 text", "h\x00\x00\xd8i\x00", []Option{WithEncoding(UTF16LE)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), append(tt.opts, WithMaxConsecutiveInvalid(4))...)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+		})
+	}
+
+	// Without the option the cluster is within the total limit.
+	if res, _ := Check(strings.NewReader("text \xff\xfe\xfd\xfc text"), WithMaxInvalidBytes(100)); !res.IsPlaintext {
+		t.Errorf("Check() without WithMaxConsecutiveInvalid() = %v, want true", res.IsPlaintext)
+	}
+}
+
+func TestWithControlDensityWindow(t *testing.T) {
+	text := strings.Repeat("regular terminal output line\n", 20)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"sparse control characters", strings.Repeat("output line with a bell\x07\n", 50), true},
+		{"burst within the ratio", text + "\x1b\x07\x08\x1b\x07" + text, true},
+		{"dense burst", text + strings.Repeat("\x01\x02\x03\x04", 20) + text, false},
+		{"binary throughout", strings.Repeat("\x00\x01\x02a", 100), false},
+		{"short content judged whole", "ab\x07", false},
+		{"short text", "ab\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithControlDensityWindow(64, 0.1))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+		})
+	}
+
+	// Other disallowed runes are still rejected.
+	res, _ := Check(strings.NewReader(text+"1"), WithControlDensityWindow(64, 0.1), WithUnicodeCategory(unicode.L, unicode.White_Space))
+	if res.IsPlaintext {
+		t.Errorf("Check() with a disallowed category = %v, want false", res.IsPlaintext)
+	}
+}
+
+func TestWithNormalizeForMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		endings LineEndings
+		mixed   bool
+	}{
+		{"LF", "id,name\n1,a\n2,b\n3,c\n", LineEndings{LF: 4}, false},
+		{"CRLF", "id,name\r\n1,a\r\n2,b\r\n3,c\r\n", LineEndings{CRLF: 4}, false},
+		{"CR", "id,name\r1,a\r2,b\r3,c\r", LineEndings{CR: 4}, false},
+		{"mixed", "id,name\r\n1,a\r2,b\n3,c\r\n", LineEndings{LF: 1, CRLF: 2, CR: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A one-byte buffer splits every CRLF pair across reads.
+			for _, buffer := range [][]byte{nil, make([]byte, 1)} {
+				o := buildOptions([]Option{WithNormalizeForMetrics(), WithDetectDelimited()})
+				res, err := check(strings.NewReader(tt.content), buffer, o)
+				if err != nil {
+					t.Fatalf("check() error: %v", err)
+				}
+				if res.Lines != 4 {
+					t.Errorf("check().Lines = %d, want 4", res.Lines)
+				}
+				if res.LineEndings != tt.endings {
+					t.Errorf("check().LineEndings = %+v, want %+v", res.LineEndings, tt.endings)
+				}
+				if res.LineEndings.Mixed() != tt.mixed {
+					t.Errorf("LineEndings.Mixed() = %v, want %v", res.LineEndings.Mixed(), tt.mixed)
+				}
+				if !res.Delimited || res.Columns != 2 {
+					t.Errorf("check() Delimited = %v with %d columns, want 2 columns", res.Delimited, res.Columns)
+				}
+			}
+		})
+	}
+
+	// Without normalization a lone CR does not end a line of delimited data.
+	res, err := Check(strings.NewReader("id,name\r\n1,a\r2,b\n3,c\r\n"), WithDetectDelimited())
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if res.Delimited || res.LineEndings != (LineEndings{}) {
+		t.Errorf("Check() = %+v, want no delimited data and no line ending counts", res)
+	}
+}
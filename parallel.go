@@ -0,0 +1,93 @@
+package isplaintextfile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// minParallelRange is the smallest range FileParallel hands to a worker, so small files are not
+// split into more ranges than is worth the overhead.
+const minParallelRange = 64 * 1024
+
+// FileParallel checks if the file at the given path is plaintext, splitting it into up to workers
+// ranges that are validated concurrently. Each range starts on a rune boundary, found by
+// backtracking from its nominal offset over UTF-8 continuation bytes, so no rune is split between
+// two ranges. The file is rejected if any range is, and the remaining ranges stop early.
+func FileParallel(path string, workers int) (bool, error) {
+	if workers <= 0 {
+		return true, fmt.Errorf("%w: workers must be greater than 0", ErrInvalidLength)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	size := info.Size()
+	workers = int(max(min(int64(workers), size/minParallelRange), 1))
+
+	starts := make([]int64, workers+1)
+	for i := 1; i < workers; i++ {
+		if starts[i], err = alignRangeStart(file, size*int64(i)/int64(workers)); err != nil {
+			return false, err
+		}
+	}
+	starts[workers] = size
+
+	var rejected atomic.Bool
+	results := make([]bool, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Go(func() {
+			section := io.NewSectionReader(file, starts[i], starts[i+1]-starts[i])
+			reader := &abortReader{r: section, abort: &rejected}
+			results[i], errs[i] = isPlaintextFromReader(reader, newReadBuffer(section, 0))
+			if !results[i] {
+				rejected.Store(true)
+			}
+		})
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil || !results[i] {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// alignRangeStart returns the offset of the rune containing the byte at offset, backtracking over
+// up to utf8.UTFMax-1 bytes. The offset is returned unchanged if no rune starts within that
+// distance, as the bytes are invalid UTF-8 either way.
+func alignRangeStart(file io.ReaderAt, offset int64) (int64, error) {
+	from := max(offset-(utf8.UTFMax-1), 0)
+	buffer := make([]byte, offset-from)
+	if _, err := file.ReadAt(buffer, from); err != nil {
+		return 0, err
+	}
+	return from + int64(runeBoundary(buffer)), nil
+}
+
+// abortReader reads from r until abort is set, and then reports the end of the content.
+type abortReader struct {
+	r     io.Reader
+	abort *atomic.Bool
+}
+
+func (a *abortReader) Read(p []byte) (int, error) {
+	if a.abort.Load() {
+		return 0, io.EOF
+	}
+	return a.r.Read(p)
+}
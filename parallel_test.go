@@ -0,0 +1,83 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileParallel(t *testing.T) {
+	// Multibyte runes of every width make most nominal range offsets fall inside a rune.
+	text := bytes.Repeat([]byte("Large text with é, 你好 and 👋 runes\n"), 64*1024)
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"large text", text, true},
+		{"binary only in the last range", append(append([]byte{}, text...), 0x00, 0x01, 0x02), false},
+		{"binary in the first range", append([]byte{0x07}, text...), false},
+		{"invalid UTF-8 in the middle", bytes.Join([][]byte{text[:len(text)/2], text[len(text)/2:]}, []byte{0xff}), false},
+		{"small text", []byte("small 👋\n"), true},
+		{"empty", nil, true},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_"))
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			for _, workers := range []int{1, 4, 7} {
+				res, err := FileParallel(path, workers)
+				if err != nil {
+					t.Errorf("FileParallel(%d) error: %v", workers, err)
+				}
+				if res != tt.expected {
+					t.Errorf("FileParallel(%d) = %v, want %v", workers, res, tt.expected)
+				}
+			}
+		})
+	}
+
+	if _, err := FileParallel(filepath.Join(dir, "missing.txt"), 4); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("FileParallel() error = %v, want %v", err, os.ErrNotExist)
+	}
+	if res, err := FileParallel(filepath.Join(dir, "missing.txt"), 0); !res || !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("FileParallel() = %v, %v, want true, %v", res, err, ErrInvalidLength)
+	}
+}
+
+func TestAlignRangeStart(t *testing.T) {
+	content := strings.NewReader("a👋b\x80\x80\x80\x80c")
+
+	tests := []struct {
+		offset   int64
+		expected int64
+	}{
+		{0, 0},
+		{1, 1},
+		{2, 1},
+		{4, 1},
+		{5, 5},
+		{6, 6},
+		{9, 9},
+		{10, 10},
+	}
+
+	for _, tt := range tests {
+		got, err := alignRangeStart(content, tt.offset)
+		if err != nil {
+			t.Errorf("alignRangeStart(%d) error: %v", tt.offset, err)
+		}
+		if got != tt.expected {
+			t.Errorf("alignRangeStart(%d) = %d, want %d", tt.offset, got, tt.expected)
+		}
+	}
+}
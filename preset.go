@@ -0,0 +1,25 @@
+package isplaintextfile
+
+// Preset returns a named bundle of options for config-driven applications, or nil if the name is
+// not recognized. The options are passed to Check and the other functions that take options.
+//
+//   - "strict": rejects any NUL byte at once and a byte order mark anywhere but the start.
+//   - "lenient": WithHeuristicMode, which tolerates a few stray control characters and invalid
+//     bytes, plus form feed and vertical tab.
+//   - "source-code": accepts form feed page breaks and rejects an interior byte order mark,
+//     which usually indicates concatenated files.
+//   - "logs": accepts ANSI color escape sequences and form feed. Lines of any length are
+//     accepted by every preset.
+func Preset(name string) []Option {
+	switch name {
+	case "strict":
+		return []Option{WithNULFastReject(), WithRejectInteriorBOM()}
+	case "lenient":
+		return []Option{WithHeuristicMode(), WithAllowedControlChars('\f', '\v')}
+	case "source-code":
+		return []Option{WithAllowedControlChars('\f'), WithRejectInteriorBOM()}
+	case "logs":
+		return []Option{WithRecognizeANSIEscapes(), WithAllowedControlChars('\f')}
+	}
+	return nil
+}
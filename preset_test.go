@@ -0,0 +1,49 @@
+package isplaintextfile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreset(t *testing.T) {
+	colored := "\x1b[31mERROR\x1b[0m connection refused\n"
+	longLine := strings.Repeat("x", 100000) + "\n"
+
+	tests := []struct {
+		name     string
+		preset   string
+		content  string
+		expected bool
+	}{
+		{"strict plain text", "strict", "Hello, World!\n", true},
+		{"strict ANSI", "strict", colored, false},
+		{"strict interior BOM", "strict", "a\uFEFFb", false},
+		{"lenient stray control character", "lenient", "mostly text\x07 with a bell\n", true},
+		{"lenient binary", "lenient", "\x00\x01\x02\x03", false},
+		{"source-code form feed", "source-code", "package main\n\f\nfunc main() {}\n", true},
+		{"source-code interior BOM", "source-code", "a\uFEFFb", false},
+		{"logs ANSI", "logs", colored, true},
+		{"logs long line", "logs", longLine, true},
+		{"logs binary", "logs", "\x00\x01\x02\x03", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Preset(tt.preset)
+			if opts == nil {
+				t.Fatalf("Preset(%q) = nil", tt.preset)
+			}
+			res, err := Check(strings.NewReader(tt.content), opts...)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Check() with Preset(%q) = %v, want %v", tt.preset, res.IsPlaintext, tt.expected)
+			}
+		})
+	}
+
+	if opts := Preset("unknown"); opts != nil {
+		t.Errorf("Preset(%q) = %v, want nil", "unknown", opts)
+	}
+}
@@ -0,0 +1,31 @@
+package isplaintextfile
+
+import (
+	"io"
+	"net/http"
+)
+
+// replayBody is a request body that yields buffered bytes before the rest of the original body,
+// which it closes when closed.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// Request checks if up to maxKB kilobytes of the request body are plaintext, or all of it for
+// NoLimit. The bytes consumed by the check are buffered and r.Body is replaced with a body that
+// replays them before the rest of the original body, so downstream handlers still see the full body.
+func Request(r *http.Request, maxKB int) (bool, error) {
+	if err := validatePreviewKB(maxKB); err != nil {
+		return true, err
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return true, nil
+	}
+
+	// The preview limit is applied last, so the check sees where it cuts the content.
+	rr := &recordingReader{r: r.Body}
+	res, err := isPlaintextFromReader(limitPreview(rr, maxKB), nil)
+	r.Body = replayBody{io.MultiReader(&rr.buf, r.Body), r.Body}
+	return res, err
+}
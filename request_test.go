@@ -0,0 +1,48 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"plain ASCII text", []byte("Hello, World!\n"), true},
+		{"binary content", []byte{0x00, 0x01, 0x02, 0x03}, false},
+		{"binary after preview", append(bytes.Repeat([]byte("A"), 1024), 0x00), true},
+		{"multibyte rune cut by the preview", append(bytes.Repeat([]byte("a"), 1023), "\u00e9 and more text"...), true},
+		{"empty body", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/upload", bytes.NewReader(tt.content))
+
+			res, err := Request(req, 1)
+			if err != nil {
+				t.Errorf("Request() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("Request() = %v, want %v", res, tt.expected)
+			}
+
+			// The handler must still see the full body.
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if !bytes.Equal(body, tt.content) {
+				t.Errorf("Request() body = %q, want %q", body, tt.content)
+			}
+			if err := req.Body.Close(); err != nil {
+				t.Errorf("Body.Close() error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package isplaintextfile
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Reason explains a verdict that was reached without examining the content normally.
+type Reason string
+
+const (
+	// SkippedTooLarge means the file exceeded the maximum file size and was not read.
+	SkippedTooLarge Reason = "skipped-too-large"
+
+	// ForcedText means the file path matched a WithForceText pattern and the file was not read.
+	ForcedText Reason = "forced-text"
+
+	// ForcedBinary means the file path matched a WithForceBinary pattern and the file was not read.
+	ForcedBinary Reason = "forced-binary"
+)
+
+// Result describes the outcome of classifying content.
+type Result struct {
+	// IsPlaintext reports whether the examined content is plaintext.
+	IsPlaintext bool
+
+	// Classification is PlainText for plaintext and Binary otherwise, except for content starting
+	// with the signature of a document format such as PDF or RTF, which is StructuredDocument
+	// regardless of IsPlaintext. Use Category for a finer classification of plaintext.
+	Classification Cat
+
+	// Encoding is the encoding the content was decoded from before validation.
+	Encoding Encoding
+
+	// Confidence is how confident the encoding choice is, from 0 to 1. A byte order mark or an
+	// explicitly configured or declared encoding is 1, valid UTF-8 found by auto-detection is high,
+	// and the Latin1 fallback is low. It is 0 when no encoding detection was performed.
+	Confidence float64
+
+	// Endianness is the byte order of content decoded from UTF-16 or UTF-32. For UTF-16 without
+	// a byte order mark it is inferred from the position of NUL bytes, and Confidence reflects
+	// how clear-cut that was.
+	Endianness Endianness
+
+	// NeedsReencoding reports that the content is plaintext decoded from an encoding other than
+	// UTF-8, such as Latin-1 found by auto-detection, and converting it to UTF-8 changes its bytes.
+	// ASCII content decoded from an encoding that keeps ASCII as is, such as Latin-1, does not
+	// need re-encoding; in UTF-16, UTF-32, or EBCDIC it always does.
+	NeedsReencoding bool
+
+	// RuneCount is the number of runes validated. In preview mode it counts runes within the previewed window.
+	RuneCount int64
+
+	// Lines is the number of lines examined: each LF, CR, or CRLF line break ends a line, and
+	// content after the last break counts as a final partial line. Unicode line separators also
+	// end lines when WithUnicodeLineSeparators is used.
+	Lines int
+
+	// LineEndings counts the LF, CRLF, and lone CR line breaks when WithNormalizeForMetrics is
+	// used, independently of how the line-based metrics treat them.
+	LineEndings LineEndings
+
+	// TrailingWhitespaceLines lists the 1-based numbers of lines ending in spaces or tabs when
+	// WithTrackTrailingWhitespace is used, up to a bound of 1000 lines.
+	TrailingWhitespaceLines []int
+
+	// TrailingWhitespaceCount is the number of lines ending in spaces or tabs when
+	// WithTrackTrailingWhitespace is used, including any beyond the bound of the list.
+	TrailingWhitespaceCount int
+
+	// IndentStyle is how the lines are indented when WithDetectIndentation is used.
+	IndentStyle IndentStyle
+
+	// IndentWidth is the inferred number of spaces per indentation level when IndentStyle is
+	// Spaces, or 0 if it could not be inferred.
+	IndentWidth int
+
+	// Script is the Unicode script with the most letters in the content, such as "Latin" or
+	// "Han", when WithDetectScript is used. It is empty if the content has no letters.
+	Script string
+
+	// NotNormalized reports that the text is not in Unicode Normalization Form C, when
+	// WithRequireNFC is used.
+	NotNormalized bool
+
+	// MaxByteWidth is the narrowest character storage that holds all of the text: 7-bit ASCII,
+	// 8-bit Latin-1, or multibyte, when WithDetectByteWidth is used and the content is plaintext.
+	MaxByteWidth ByteWidth
+
+	// StructuredIndentLikely reports that the text looks like YAML or Python: indented with
+	// spaces in consistent steps of 2 or 4, with many lines of keys or block headers ending in a
+	// colon, when WithDetectStructuredIndent is used.
+	StructuredIndentLikely bool
+
+	// ContainsTemplateMarkers reports that the text contains unrendered interpolation markers,
+	// "{{ }}" or "${ }", when WithDetectTemplateMarkers is used.
+	ContainsTemplateMarkers bool
+
+	// Words is the number of runs of non-whitespace runes in plaintext, when WithCountWords is
+	// used.
+	Words int64
+
+	// Delimited reports that the content looks like delimited data such as CSV, with Delimiter
+	// splitting every line into Columns fields, when WithDetectDelimited is used.
+	Delimited bool
+	Delimiter rune
+	Columns   int
+
+	// RejectedFormatChar is the first format character, such as U+00AD SOFT HYPHEN, that was
+	// disallowed by WithRejectFormatChars in content that is not plaintext, or 0 if there was none.
+	RejectedFormatChar rune
+
+	// InvalidBytes is the number of bytes that were not valid UTF-8 and were tolerated, or
+	// that caused rejection once the tolerance was exceeded.
+	InvalidBytes int64
+
+	// Incomplete reports that the scan ended before the end of the content,
+	// so the verdict covers only the bytes that were read.
+	Incomplete bool
+
+	// TimedOut reports that the time budget ran out before the end of the content.
+	// Incomplete is also set.
+	TimedOut bool
+
+	// EarlyAccepted reports that the content was accepted as plaintext once the number of clean
+	// bytes set by WithStopWhenTextBytes had been scanned. Incomplete is also set.
+	EarlyAccepted bool
+
+	// FinalNewline reports whether the last byte examined was a line feed, or the content ended in
+	// a Unicode line separator when WithUnicodeLineSeparators is used.
+	// In preview mode this describes the end of the previewed window, not the end of the file.
+	// It is false for content rejected before its end, which was not examined to the last byte.
+	FinalNewline bool
+
+	// PrintableRatio is the fraction of the examined runes and invalid bytes that were printable.
+	// It is 1 for empty content.
+	PrintableRatio float64
+
+	// TrailingIgnored is the number of non-text bytes at the end of the content that were
+	// ignored under the trailing tolerance.
+	TrailingIgnored int64
+
+	// WhitespaceOnly reports that the content is plaintext and every rune in it is whitespace.
+	// Empty content is whitespace only.
+	WhitespaceOnly bool
+
+	// ContainsANSI reports that well-formed ANSI escape sequences were accepted as text.
+	ContainsANSI bool
+
+	// Snippet holds the first runes of plaintext content when WithSnippet is used.
+	// It is empty for content that is not plaintext.
+	Snippet string
+
+	// Reason explains a verdict reached without examining the content normally.
+	// It is empty when the content was scanned.
+	Reason Reason
+}
+
+// ignoredInComparison lists the Result fields that Equal and Diff ignore: TimedOut depends on how
+// fast the content was read, and Snippet is a preview for display rather than part of the verdict.
+var ignoredInComparison = map[string]bool{
+	"TimedOut": true,
+	"Snippet":  true,
+}
+
+// Equal reports whether the result and other are semantically the same, for test assertions. It
+// compares every field except TimedOut and Snippet, which can be compared directly when they
+// matter. A nil slice equals an empty one.
+func (r Result) Equal(other Result) bool {
+	return r.Diff(other) == ""
+}
+
+// Diff describes the fields that differ between the result and other, one per line in the form
+// "Lines: 3 != 4" with the value of r first, or returns an empty string if Equal would report
+// true. It is meant for readable test failures.
+func (r Result) Diff(other Result) string {
+	var b strings.Builder
+	got, want := reflect.ValueOf(r), reflect.ValueOf(other)
+	for i := range got.NumField() {
+		field := got.Type().Field(i)
+		if ignoredInComparison[field.Name] {
+			continue
+		}
+		a, o := got.Field(i).Interface(), want.Field(i).Interface()
+		if field.Type.Kind() == reflect.Slice && got.Field(i).Len() == 0 && want.Field(i).Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(a, o) {
+			fmt.Fprintf(&b, "%s: %v != %v\n", field.Name, a, o)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,490 @@
+package isplaintextfile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestResultFinalNewline(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"ends with LF", "line one\nline two\n", true},
+		{"ends with CRLF", "line one\r\nline two\r\n", true},
+		{"no trailing newline", "line one\nline two", false},
+		{"empty", "", false},
+		{"rejected before a final LF", "line one\x00line two\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write temp file: %v", err)
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatalf("Failed to open temp file: %v", err)
+			}
+			defer file.Close()
+
+			res, err := Check(file)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.FinalNewline != tt.expected {
+				t.Errorf("Check().FinalNewline = %v, want %v", res.FinalNewline, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResultRuneCount(t *testing.T) {
+	tests := []string{
+		"",
+		"Hello, World!\n",
+		"Hello 👋 World! 🌍\n",
+		"你好，世界！\n",
+	}
+
+	for _, content := range tests {
+		res, err := Check(strings.NewReader(content))
+		if err != nil {
+			t.Errorf("Check(%q) error: %v", content, err)
+		}
+		if want := int64(utf8.RuneCountInString(content)); res.RuneCount != want {
+			t.Errorf("Check(%q).RuneCount = %d, want %d", content, res.RuneCount, want)
+		}
+	}
+
+	// A preview counts only the runes within the window.
+	res, err := Check(io.LimitReader(strings.NewReader("abc👋def"), 7))
+	if err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+	if res.RuneCount != 4 {
+		t.Errorf("Check().RuneCount = %d, want 4", res.RuneCount)
+	}
+}
+
+func TestResultWhitespaceOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"spaces tabs and newlines", "  \t\n\r\n   \t\t\n", true},
+		{"unicode whitespace", "\u2003\u00a0\n", true},
+		{"empty", "", true},
+		{"one letter", "   \n\tx\n  ", false},
+		{"binary", "  \x00  ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.WhitespaceOnly != tt.expected {
+				t.Errorf("Check().WhitespaceOnly = %v, want %v", res.WhitespaceOnly, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResultSnippet(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		n        int
+		expected string
+	}{
+		{"ascii", "Hello, World!\n", 5, "Hello"},
+		{"multibyte", "你好，世界！\n", 3, "你好，"},
+		{"emoji", "Hi 👋 there", 4, "Hi 👋"},
+		{"shorter than n", "abc", 10, "abc"},
+		{"disabled", "abc", 0, ""},
+		{"binary", "abc\x00def", 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A one-byte buffer splits every multibyte rune across reads.
+			res, err := check(strings.NewReader(tt.content), make([]byte, 1), buildOptions([]Option{WithSnippet(tt.n)}))
+			if err != nil {
+				t.Fatalf("check() error: %v", err)
+			}
+			if res.Snippet != tt.expected {
+				t.Errorf("check().Snippet = %q, want %q", res.Snippet, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectWithLineCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+	}{
+		{"five lines", "one\ntwo\nthree\nfour\nfive\n", 5},
+		{"CRLF", "one\r\ntwo\r\nthree\r\n", 3},
+		{"partial final line", "one\ntwo", 2},
+		{"blank lines", "a\r\n\r\nb", 3},
+		{"lone CR", "a\rb", 2},
+		{"empty", "", 0},
+		{"single newline", "\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A one-byte buffer splits every CRLF pair across reads.
+			for _, buffer := range [][]byte{nil, make([]byte, 1)} {
+				res, err := check(strings.NewReader(tt.content), buffer, buildOptions(nil))
+				if err != nil {
+					t.Fatalf("check() error: %v", err)
+				}
+				if res.Lines != tt.expected {
+					t.Errorf("check().Lines = %d, want %d", res.Lines, tt.expected)
+				}
+			}
+		})
+	}
+
+	res, err := DetectWithLineCount(strings.NewReader("one\ntwo\nthree\nfour\nfive\n"))
+	if err != nil {
+		t.Errorf("DetectWithLineCount() error: %v", err)
+	}
+	if !res.IsPlaintext || res.Lines != 5 {
+		t.Errorf("DetectWithLineCount() = %+v, want 5 lines of plaintext", res)
+	}
+
+	// Unicode line separators end lines only when enabled.
+	res, _ = Check(strings.NewReader("one\u2028two\u2028"), WithUnicodeLineSeparators())
+	if res.Lines != 2 {
+		t.Errorf("Check().Lines = %d, want 2", res.Lines)
+	}
+}
+
+func TestDetectWithWordCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected int64
+	}{
+		{"three words", "hello world foo", 3},
+		{"surrounding whitespace", "  hello\tworld\n\nfoo \n", 3},
+		{"punctuation joins words", "don't stop-believing, ok?", 3},
+		{"no-break space", "hello\u00a0world", 2},
+		{"ideographic space", "\u4f60\u597d\u3000\u4e16\u754c", 2},
+		{"leading byte order mark", "\uFEFF hello", 1},
+		{"whitespace only", " \n\t", 0},
+		{"empty", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A one-byte buffer splits multibyte whitespace across reads.
+			for _, buffer := range [][]byte{nil, make([]byte, 1)} {
+				res, err := check(strings.NewReader(tt.content), buffer, buildOptions([]Option{WithCountWords()}))
+				if err != nil {
+					t.Fatalf("check() error: %v", err)
+				}
+				if res.Words != tt.expected {
+					t.Errorf("check().Words = %d, want %d", res.Words, tt.expected)
+				}
+			}
+		})
+	}
+
+	res, err := DetectWithWordCount(strings.NewReader("hello world foo"))
+	if err != nil {
+		t.Errorf("DetectWithWordCount() error: %v", err)
+	}
+	if !res.IsPlaintext || res.Words != 3 {
+		t.Errorf("DetectWithWordCount() = %+v, want 3 words of plaintext", res)
+	}
+
+	// Words are only reported for plaintext.
+	if res, _ := DetectWithWordCount(strings.NewReader("hello\x00world")); res.Words != 0 {
+		t.Errorf("DetectWithWordCount().Words = %d for binary content, want 0", res.Words)
+	}
+}
+
+func TestResultIndentation(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expected      IndentStyle
+		expectedWidth int
+	}{
+		{"four spaces", "func main() {\n    if ok {\n        run()\n    }\n}\n", Spaces, 4},
+		{"two spaces", "a:\n  b:\n    c: 1\n  d: 2\n", Spaces, 2},
+		{"tabs", "func main() {\n\tif ok {\n\t\trun()\n\t}\n}\n", Tabs, 0},
+		{"mixed lines", "a\n\tb\n    c\n", Mixed, 0},
+		{"mixed within a line", "a\n\t  b\n", Mixed, 0},
+		{"no indentation", "one\ntwo\n\n", None, 0},
+		{"whitespace only lines ignored", "a\n    b\n\t\n", Spaces, 4},
+		{"CRLF", "a\r\n    b\r\n", Spaces, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithDetectIndentation())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.IndentStyle != tt.expected || res.IndentWidth != tt.expectedWidth {
+				t.Errorf("Check() indentation = %v %d, want %v %d", res.IndentStyle, res.IndentWidth, tt.expected, tt.expectedWidth)
+			}
+		})
+	}
+}
+
+func TestResultClassification(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expected      Cat
+		expectedPlain bool
+	}{
+		{"plain text", "Hello, World!\n", PlainText, true},
+		{"binary", "\x00\x01\x02\x03", Binary, false},
+		{"PDF prefix", "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n", StructuredDocument, false},
+		{"ASCII-only PDF", "%PDF-1.4\n1 0 obj\n<< >>\nendobj\n", StructuredDocument, true},
+		{"RTF prefix", "{\\rtf1\\ansi\\deff0 Hello}\n", StructuredDocument, true},
+		{"truncated signature", "%P", PlainText, true},
+		{"PDF mentioned later", "see %PDF-1.4\n", PlainText, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A one-byte buffer splits the signature across reads.
+			res, err := check(strings.NewReader(tt.content), make([]byte, 1), buildOptions(nil))
+			if err != nil {
+				t.Errorf("check() error: %v", err)
+			}
+			if res.Classification != tt.expected {
+				t.Errorf("check().Classification = %v, want %v", res.Classification, tt.expected)
+			}
+			if res.IsPlaintext != tt.expectedPlain {
+				t.Errorf("check().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expectedPlain)
+			}
+		})
+	}
+}
+
+func TestResultScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"English", "The quick brown fox jumps over the lazy dog.\n", "Latin"},
+		{"Chinese", "你好，世界！今天天气很好。\n", "Han"},
+		{"Russian", "Привет, мир!\n", "Cyrillic"},
+		{"Arabic", "مرحبا بالعالم\n", "Arabic"},
+		{"mostly Latin", "Hello 世界, how are you today?\n", "Latin"},
+		{"no letters", "12345 !?\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithDetectScript())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.Script != tt.expected {
+				t.Errorf("Check().Script = %q, want %q", res.Script, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResultDelimited(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		delimiter rune
+		columns   int
+	}{
+		{"CSV", "name,age,city\nAlice,30,Paris\nBob,25,Berlin\n", ',', 3},
+		{"CSV with CRLF and no final newline", "a,b,c\r\n1,2,3\r\n4,5,6", ',', 3},
+		{"CSV with quoted fields", "name,quote\n\"Smith, Jane\",\"said \"\"hi\"\"\"\nBob,\"line one\nline two\"\n", ',', 2},
+		{"TSV", "id\tvalue\n1\tone\n2\ttwo\n", '\t', 2},
+		{"pipe delimited", "a|b|c|d\n1|2|3|4\n", '|', 4},
+		{"blank lines skipped", "a,b\n\n1,2\n\n", ',', 2},
+		{"inconsistent counts", "a,b,c\n1,2\n", 0, 0},
+		{"single line", "a,b,c\n", 0, 0},
+		{"prose", "Hello, world.\nNothing to see here.\n", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithDetectDelimited())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.Delimited != (tt.columns > 0) || res.Delimiter != tt.delimiter || res.Columns != tt.columns {
+				t.Errorf("Check() = Delimited %v, Delimiter %q, Columns %d, want delimiter %q and %d columns",
+					res.Delimited, res.Delimiter, res.Columns, tt.delimiter, tt.columns)
+			}
+		})
+	}
+
+	// Content that is not plaintext is not delimited data.
+	if res, _ := Check(strings.NewReader("a,b\n1,2\x00\n"), WithDetectDelimited()); res.Delimited {
+		t.Errorf("Check().Delimited = %v, want false", res.Delimited)
+	}
+}
+
+func TestResultEqual(t *testing.T) {
+	base := Result{IsPlaintext: true, Classification: PlainText, Encoding: UTF8, RuneCount: 12, Lines: 2, PrintableRatio: 1}
+
+	withLines := base
+	withLines.Lines = 3
+	withVerdict := base
+	withVerdict.IsPlaintext, withVerdict.Classification = false, Binary
+	withSnippet := base
+	withSnippet.Snippet, withSnippet.TimedOut = "Hello", true
+	withEmptySlice := base
+	withEmptySlice.TrailingWhitespaceLines = []int{}
+	withSlice := base
+	withSlice.TrailingWhitespaceLines = []int{1}
+
+	tests := []struct {
+		name     string
+		other    Result
+		expected bool
+		diff     string
+	}{
+		{"identical", base, true, ""},
+		{"different line count", withLines, false, "Lines: 2 != 3"},
+		{"different verdict", withVerdict, false, "IsPlaintext: true != false\nClassification: PlainText != Binary"},
+		{"snippet and timing ignored", withSnippet, true, ""},
+		{"nil and empty slices", withEmptySlice, true, ""},
+		{"different slices", withSlice, false, "TrailingWhitespaceLines: [] != [1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Equal(tt.other); got != tt.expected {
+				t.Errorf("Equal() = %v, want %v", got, tt.expected)
+			}
+			if got := tt.other.Equal(base); got != tt.expected {
+				t.Errorf("Equal() reversed = %v, want %v", got, tt.expected)
+			}
+			if got := base.Diff(tt.other); got != tt.diff {
+				t.Errorf("Diff() = %q, want %q", got, tt.diff)
+			}
+		})
+	}
+
+	// Results of the same content are equal.
+	a, _ := Check(strings.NewReader("same content\n"), WithSnippet(4))
+	b, _ := Check(strings.NewReader("same content\n"))
+	if !a.Equal(b) {
+		t.Errorf("Equal() = false, want true; diff:\n%s", a.Diff(b))
+	}
+}
+
+func TestResultMaxByteWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		opts     []Option
+		expected ByteWidth
+	}{
+		{"pure ASCII", "plain ASCII text\n", nil, SevenBit},
+		{"Latin-1 range decoded as UTF-8", "café crème brûlée\n", nil, EightBit},
+		{"Latin-1 encoded", "caf\xe9\n", []Option{WithEncoding(Latin1)}, EightBit},
+		{"Chinese", "你好\n", nil, Multibyte},
+		{"emoji", "Hello 👋\n", nil, Multibyte},
+		{"empty", "", nil, SevenBit},
+		{"binary", "\x00\x01", nil, UnknownWidth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), append(tt.opts, WithDetectByteWidth())...)
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if res.MaxByteWidth != tt.expected {
+				t.Errorf("Check().MaxByteWidth = %v, want %v", res.MaxByteWidth, tt.expected)
+			}
+		})
+	}
+
+	// Pure ASCII read through the fast path is still 7-bit.
+	res, _ := Check(strings.NewReader("plain ASCII text\n"), WithDetectByteWidth(), WithASCIIFastPath())
+	if res.MaxByteWidth != SevenBit {
+		t.Errorf("Check().MaxByteWidth with fast path = %v, want %v", res.MaxByteWidth, SevenBit)
+	}
+	if res, _ := Check(strings.NewReader("plain\n")); res.MaxByteWidth != UnknownWidth {
+		t.Errorf("Check().MaxByteWidth without option = %v, want %v", res.MaxByteWidth, UnknownWidth)
+	}
+}
+
+func TestResultContainsTemplateMarkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"Go template", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}-config\n", true},
+		{"trimmed Go template", "{{- if .Values.enabled -}}\nenabled: true\n{{- end }}\n", true},
+		{"shell interpolation", "url = \"https://${host}:8080\"\n", true},
+		{"plain config", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n", false},
+		{"JSON", "{\"a\": {\"b\": {\"c\": 1}}}\n", false},
+		{"unclosed marker", "literal {{ without a close\n", false},
+		{"dollar without a brace", "price: $5 {total}\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithDetectTemplateMarkers())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if !res.IsPlaintext || res.ContainsTemplateMarkers != tt.expected {
+				t.Errorf("Check() = %+v, want plaintext with ContainsTemplateMarkers %v", res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResultStructuredIndentLikely(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"YAML", "server:\n  host: localhost\n  port: 8080\n  tls:\n    enabled: true\n    cert: /etc/cert.pem\nlogging:\n  level: info\n", true},
+		{"YAML with lists", "steps:\n  - name: build\n    run: make\n  - name: test\n    run: make test\n", true},
+		{"Python", "class Greeter:\n    def greet(self, name):\n        if name:\n            return 'Hello ' + name\n        return 'Hello'\n", true},
+		{"prose", "It was a bright cold day in April, and the clocks were striking thirteen.\nWinston Smith slipped quickly through the glass doors.\n", false},
+		{"indented prose", "Dear reader,\n   this letter is indented\n by an uneven number of spaces\n     throughout the text.\n", false},
+		{"tab-indented config", "server:\n\thost: localhost\n\tport: 8080\n", false},
+		{"flat key-value pairs", "host: localhost\nport: 8080\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Check(strings.NewReader(tt.content), WithDetectStructuredIndent())
+			if err != nil {
+				t.Errorf("Check() error: %v", err)
+			}
+			if !res.IsPlaintext || res.StructuredIndentLikely != tt.expected {
+				t.Errorf("Check() = %+v, want plaintext with StructuredIndentLikely %v", res, tt.expected)
+			}
+		})
+	}
+}
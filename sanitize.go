@@ -0,0 +1,33 @@
+package isplaintextfile
+
+import (
+	"unicode/utf8"
+)
+
+// Sanitize returns a copy of data made into plaintext under the options. Control characters
+// mapped by WithControlReplacements are replaced with their mapped bytes, even if they would be
+// allowed. Every other byte that is not valid UTF-8, and every rune that is not allowed, is
+// replaced with the Unicode replacement character U+FFFD. Contradictory options return an
+// *OptionError.
+func Sanitize(data []byte, opts ...Option) ([]byte, error) {
+	o := buildOptions(opts)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	s := scanner{opts: o}
+	out := make([]byte, 0, len(data))
+
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if repl, ok := s.opts.ControlReplacements[data[0]]; ok && r < utf8.RuneSelf {
+			out = append(out, repl...)
+		} else if r == utf8.RuneError && size == 1 || !s.allowed(r) {
+			out = utf8.AppendRune(out, utf8.RuneError)
+		} else {
+			out = append(out, data[:size]...)
+		}
+		s.runes++
+		data = data[size:]
+	}
+	return out, nil
+}
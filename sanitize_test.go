@@ -0,0 +1,45 @@
+package isplaintextfile
+
+import (
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	replacements := map[byte][]byte{
+		'\t': []byte("    "),
+		'\r': nil,
+		0x07: []byte("⍾"),
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		opts     []Option
+		expected string
+	}{
+		{"plaintext unchanged", "Hello, 世界!\n", nil, "Hello, 世界!\n"},
+		{"control character", "a\x00b", nil, "a�b"},
+		{"invalid UTF-8", "caf\xe9\n", nil, "caf�\n"},
+		{"allowed control character", "a\fb", []Option{WithAllowedControlChars('\f')}, "a\fb"},
+		{"tab replaced with spaces", "a\tb\n", []Option{WithControlReplacements(replacements)}, "a    b\n"},
+		{"carriage return removed", "a\r\nb\r\n", []Option{WithControlReplacements(replacements)}, "a\nb\n"},
+		{"bell replaced with glyph", "ding\x07", []Option{WithControlReplacements(replacements)}, "ding⍾"},
+		{"unmapped control character", "a\x01b\tc", []Option{WithControlReplacements(replacements)}, "a�b    c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sanitized, err := Sanitize([]byte(tt.content), tt.opts...)
+			if err != nil {
+				t.Fatalf("Sanitize(%q) error: %v", tt.content, err)
+			}
+			got := string(sanitized)
+			if got != tt.expected {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.content, got, tt.expected)
+			}
+			if res, _ := checkBytes([]byte(got), buildOptions(tt.opts)); !res.IsPlaintext {
+				t.Errorf("Sanitize(%q) = %q, which is not plaintext", tt.content, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,687 @@
+package isplaintextfile
+
+import (
+	"slices"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// scanner incrementally validates plaintext fed to it in arbitrarily sized chunks.
+// A rune split across two chunks is carried over and validated once complete.
+type scanner struct {
+	opts Options
+
+	// detection describes the encoding the content was decoded from; an empty encoding means it was validated as UTF-8.
+	detection detection
+
+	// deadline, when set, is when the scan stops and reports the verdict so far.
+	deadline time.Time
+
+	// idle, when set, ends the content once the reader stalls for the idle timeout.
+	idle *idleReader
+
+	// preview, when set, limits the content to a preview that may cut its last rune.
+	preview *previewReader
+
+	// utf8Only disables the control character policy so only UTF-8 validity is checked.
+	utf8Only bool
+
+	carry    [utf8.UTFMax]byte
+	carryLen int
+	failed   bool
+	lastByte byte
+
+	// sawHighByte reports that a byte outside ASCII has been fed, ending the ASCII fast path, and
+	// that the decoded content is not all ASCII.
+	sawHighByte bool
+	runes       int64
+
+	invalidBytes int64
+	sawNonSpace  bool
+
+	// invalidRun counts the invalid bytes and replacement characters since the last other rune.
+	invalidRun int
+
+	// maxRune is the largest rune accepted.
+	maxRune rune
+
+	// formatChar is the first format character disallowed by WithRejectFormatChars.
+	formatChar rune
+
+	// lineEndings counts the line breaks of each kind when WithNormalizeForMetrics is used.
+	lineEndings LineEndings
+
+	// words counts runs of non-whitespace runes when WithCountWords is used; inWord reports that
+	// the last rune accepted was part of one.
+	words  int64
+	inWord bool
+
+	// trailing is set once content has been rejected within the trailing tolerance;
+	// trailingBytes counts the bytes from the rejected position onwards.
+	trailing      bool
+	trailingBytes int64
+
+	ansi    ansiState
+	sawANSI bool
+
+	nonPrintable int64
+
+	// nonGraphic counts accepted runes that are neither graphic characters nor whitespace.
+	// printableRun counts the graphic characters and whitespace since the last other rune or
+	// invalid byte, and longestRun is the longest such run.
+	nonGraphic   int64
+	printableRun int64
+	longestRun   int64
+
+	// lastSeparator reports that the last rune accepted was a Unicode line separator, when
+	// those are enabled, and that no invalid byte followed it.
+	lastSeparator bool
+
+	// lines counts the line breaks accepted; a CRLF pair counts once. afterCR reports that the
+	// last rune was a carriage return, and lineOpen that the current line has content after the
+	// last break.
+	lines    int
+	afterCR  bool
+	lineOpen bool
+
+	// trailingSpace reports that the last rune was a space or tab. The lines ending in one are
+	// counted in trailingWSCount and listed, up to a bound, in trailingWSLines.
+	trailingSpace   bool
+	trailingWSCount int
+	trailingWSLines []int
+
+	indent    indentStats
+	keys      keyLineStats
+	scripts   scriptStats
+	nfc       nfcCheck
+	delimited delimitedStats
+	templates templateMarkers
+	density   controlDensity
+
+	// head holds the first bytes of the content, to recognize document signatures.
+	head    [documentSignatureSize]byte
+	headLen int
+
+	// snippet holds the first opts.SnippetRunes runes accepted, snippetRunes how many it holds.
+	snippet      []byte
+	snippetRunes int
+}
+
+// byteOrderMark is U+FEFF, which marks the byte order at the start of content
+// and is a zero width no-break space anywhere else.
+const byteOrderMark = '\uFEFF'
+
+// maxBMPRune is the last rune of the Basic Multilingual Plane.
+const maxBMPRune = '\uFFFF'
+
+// isRuneAllowed reports whether a decoded rune is acceptable in plaintext.
+func isRuneAllowed(r rune) bool {
+	// Check for control characters (except whitespace)
+	return r >= 32 || r == '\n' || r == '\r' || r == '\t'
+}
+
+// isLineSeparator reports whether r is one of the Unicode line separators other than line feed
+// and carriage return.
+func isLineSeparator(r rune) bool {
+	return r == '\u0085' || r == '\u2028' || r == '\u2029'
+}
+
+// asciiTable records which ASCII runes are allowed under a set of options.
+type asciiTable [utf8.RuneSelf]bool
+
+// newASCIITable evaluates the policy of the options for every ASCII rune.
+func newASCIITable(o Options) *asciiTable {
+	s := scanner{opts: o}
+	var t asciiTable
+	for r := range t {
+		t[r] = s.evaluate(rune(r))
+	}
+	return &t
+}
+
+// allowed reports whether a decoded rune is acceptable under the scanner's policy. ASCII runes
+// are looked up in the table precomputed for the options, when there is one.
+func (s *scanner) allowed(r rune) bool {
+	if r < utf8.RuneSelf && s.opts.allowedASCII != nil && !s.utf8Only {
+		return s.opts.allowedASCII[r]
+	}
+	return s.evaluate(r)
+}
+
+// evaluate applies the scanner's policy to a decoded rune.
+func (s *scanner) evaluate(r rune) bool {
+	if s.opts.UnicodeLineSeparators && isLineSeparator(r) {
+		return true
+	}
+	if !s.utf8Only && !isRuneAllowed(r) && !s.isAllowedControl(r) {
+		return false
+	}
+	if len(s.opts.AllowedCategories) > 0 && !unicode.IsOneOf(s.opts.AllowedCategories, r) {
+		return false
+	}
+	if r == byteOrderMark && s.opts.RejectInteriorBOM && s.runes > 0 {
+		return false
+	}
+	if r > maxBMPRune && s.opts.RejectAstral {
+		return false
+	}
+	if s.opts.RejectFormatChars && isFormatChar(r) && !(r == byteOrderMark && s.runes == 0) {
+		return false
+	}
+	return true
+}
+
+// isAllowedControl reports whether a control character was explicitly allowed by the options.
+func (s *scanner) isAllowedControl(r rune) bool {
+	for _, c := range s.opts.AllowedControlChars {
+		if rune(c) == r {
+			return true
+		}
+	}
+	return false
+}
+
+// accept applies the policy to the next decoded rune and records it.
+// It reports whether the content is still plaintext.
+func (s *scanner) accept(r rune) bool {
+	if s.opts.RecognizeANSIEscapes && (s.ansi != ansiNone || r == escapeChar) {
+		return s.acceptANSI(r)
+	}
+	if r == 0 && s.opts.NULFastReject {
+		s.failed = true
+		return false
+	}
+	control := false
+	if !s.allowed(r) {
+		if s.formatChar == 0 && s.opts.RejectFormatChars && isFormatChar(r) {
+			s.formatChar = r
+		}
+		switch {
+		case s.opts.ControlDensityWindow > 0 && unicode.IsControl(r):
+			// Under a density window, control characters only count against the density.
+			control = true
+		case s.opts.PrintableThreshold <= 0:
+			s.failed = true
+			return false
+		default:
+			// Under a printable threshold, disallowed runes only count against the ratio.
+			s.nonPrintable++
+			if s.hardLimitReached() {
+				s.failed = true
+				return false
+			}
+		}
+	}
+	if s.opts.ControlDensityWindow > 0 {
+		if s.density.window == nil {
+			s.density.window = make([]bool, s.opts.ControlDensityWindow)
+		}
+		s.density.add(control, utf8.RuneLen(r))
+		if s.density.exceeds(s.opts.ControlDensityMaxRatio, false) {
+			s.failed = true
+			return false
+		}
+	}
+	if r == utf8.RuneError {
+		if s.invalidRunReached() {
+			s.failed = true
+			return false
+		}
+	} else {
+		s.invalidRun = 0
+	}
+	s.runes++
+	s.lastSeparator = s.opts.UnicodeLineSeparators && isLineSeparator(r)
+	afterCR := s.afterCR
+	s.countLine(r)
+	if s.opts.runeMetadata {
+		s.trackMetadata(r, afterCR)
+	}
+	if s.snippetRunes < s.opts.SnippetRunes {
+		s.snippet = utf8.AppendRune(s.snippet, r)
+		s.snippetRunes++
+	}
+	if !s.sawNonSpace && !unicode.IsSpace(r) {
+		s.sawNonSpace = true
+	}
+	return true
+}
+
+// trackMetadata passes the next accepted rune to the metadata enabled by the options. afterCR
+// reports that the previous rune was a CR.
+func (s *scanner) trackMetadata(r rune, afterCR bool) {
+	if s.opts.DetectByteWidth {
+		s.maxRune = max(s.maxRune, r)
+	}
+	if !s.opts.NormalizeForMetrics {
+		s.trackLines(r)
+	} else if metric, ok := s.lineEndings.normalize(r, afterCR); ok {
+		s.trackLines(metric)
+	}
+	if s.opts.DetectScript {
+		s.scripts.next(r)
+	}
+	if s.opts.DetectTemplateMarkers {
+		s.templates.next(r)
+	}
+	if s.opts.CountWords {
+		s.countWord(r)
+	}
+	// The minimum printable run and ratio need to know which runes are graphic, and so does
+	// early acceptance, which only accepts clean content.
+	if s.opts.MinPrintableRun > 0 || s.opts.MinPrintableRatio > 0 || s.opts.StopWhenTextBytes > 0 {
+		s.countGraphic(r)
+	}
+}
+
+// countGraphic records whether the next accepted rune is a graphic character or whitespace.
+func (s *scanner) countGraphic(r rune) {
+	if unicode.IsGraphic(r) || unicode.IsSpace(r) {
+		s.printableRun++
+		s.longestRun = max(s.longestRun, s.printableRun)
+	} else {
+		s.nonGraphic++
+		s.printableRun = 0
+	}
+}
+
+// trackLines passes the next accepted rune to the enabled line-based metrics.
+func (s *scanner) trackLines(r rune) {
+	lineBreak := r == '\n' || r == '\r' || s.lastSeparator
+	if s.opts.DetectIndentation || s.opts.DetectStructuredIndent {
+		s.indent.next(r, lineBreak)
+	}
+	if s.opts.DetectStructuredIndent {
+		s.keys.next(r, lineBreak)
+	}
+	if s.opts.DetectDelimited {
+		s.delimited.next(r)
+	}
+}
+
+// countWord updates the word count for the next accepted rune. A word is a run of runes that are
+// not Unicode whitespace, so punctuation and digits belong to the words they touch. A byte order
+// mark at the very start is not part of a word.
+func (s *scanner) countWord(r rune) {
+	switch {
+	case unicode.IsSpace(r):
+		s.inWord = false
+	case r == byteOrderMark && s.runes == 1:
+	case !s.inWord:
+		s.inWord = true
+		s.words++
+	}
+}
+
+// countLine updates the line count for the next accepted rune.
+func (s *scanner) countLine(r rune) {
+	switch {
+	case r == '\n':
+		if !s.afterCR {
+			s.lines++
+			s.endLine()
+		}
+		s.lineOpen = false
+	case r == '\r' || s.lastSeparator:
+		s.lines++
+		s.endLine()
+		s.lineOpen = false
+	default:
+		s.lineOpen = true
+	}
+	s.afterCR = r == '\r'
+	s.trailingSpace = r == ' ' || r == '\t'
+}
+
+// endLine records trailing whitespace on the line that just ended, numbered s.lines.
+func (s *scanner) endLine() {
+	if !s.opts.TrackTrailingWhitespace || !s.trailingSpace {
+		return
+	}
+	s.trailingWSCount++
+	if len(s.trailingWSLines) < maxTrailingWhitespaceLines {
+		s.trailingWSLines = append(s.trailingWSLines, s.lines)
+	}
+}
+
+// invalid records a byte that is not valid UTF-8 and reports whether the content is still
+// plaintext, which is only the case while the invalid bytes are within the tolerance.
+func (s *scanner) invalid() bool {
+	s.invalidBytes++
+	s.lastSeparator = false
+	s.printableRun = 0
+	if s.invalidBytes > int64(s.opts.MaxInvalidBytes) || s.hardLimitReached() || s.invalidRunReached() {
+		s.failed = true
+		return false
+	}
+	return true
+}
+
+// invalidRunReached counts another invalid byte or replacement character in a row and reports
+// whether the run has reached the limit set by WithMaxConsecutiveInvalid.
+func (s *scanner) invalidRunReached() bool {
+	s.invalidRun++
+	return s.opts.MaxConsecutiveInvalid > 0 && s.invalidRun >= s.opts.MaxConsecutiveInvalid
+}
+
+// hardLimitReached reports whether the disallowed runes and invalid bytes seen have reached the
+// limit set by WithHardFailAfter.
+func (s *scanner) hardLimitReached() bool {
+	return s.opts.HardFailAfter > 0 && s.nonPrintable+s.invalidBytes >= int64(s.opts.HardFailAfter)
+}
+
+// rejectTrailing handles a rejection with n bytes fed from the rejected position onwards.
+// Within the trailing tolerance the rejection is deferred: the remaining content is only
+// counted, and it is ignored if the content ends within the tolerance.
+// It reports whether the content may still be plaintext.
+func (s *scanner) rejectTrailing(n int64) bool {
+	if s.opts.TrailingToleranceBytes <= 0 || s.hardLimitReached() {
+		return false
+	}
+	s.failed = false
+	s.trailing = true
+	s.trailingBytes = n
+	return s.checkTrailing()
+}
+
+// checkTrailing rejects the content once the trailing bytes exceed the tolerance.
+func (s *scanner) checkTrailing() bool {
+	if s.trailingBytes > int64(s.opts.TrailingToleranceBytes) {
+		s.failed = true
+		return false
+	}
+	return true
+}
+
+// feed validates the next chunk of content and reports whether everything seen so far is plaintext.
+func (s *scanner) feed(chunk []byte) bool {
+	if s.failed {
+		return false
+	}
+	if s.headLen < len(s.head) {
+		s.headLen += copy(s.head[s.headLen:], chunk)
+	}
+	if len(chunk) > 0 {
+		s.lastByte = chunk[len(chunk)-1]
+	}
+	if s.trailing {
+		s.trailingBytes += int64(len(chunk))
+		return s.checkTrailing()
+	}
+	if s.opts.RequireNFC {
+		s.nfc.write(chunk)
+	}
+
+	pos := 0
+
+	// Complete a rune left over from the previous chunk.
+	if s.carryLen > 0 {
+		carried := s.carryLen
+		for s.carryLen < utf8.UTFMax && pos < len(chunk) && !utf8.FullRune(s.carry[:s.carryLen]) {
+			s.carry[s.carryLen] = chunk[pos]
+			s.carryLen++
+			pos++
+		}
+		if !utf8.FullRune(s.carry[:s.carryLen]) {
+			return true
+		}
+		r, size := utf8.DecodeRune(s.carry[:s.carryLen])
+		s.carryLen = 0
+		if r == utf8.RuneError && size == 1 {
+			// Every carried byte is invalid on its own; the borrowed bytes are rescanned.
+			for i := range carried {
+				if !s.invalid() {
+					return s.rejectTrailing(int64(carried - i + len(chunk)))
+				}
+			}
+			pos = 0
+		} else if !s.accept(r) {
+			return s.rejectTrailing(int64(carried + len(chunk)))
+		}
+	}
+
+	if s.asciiFastPath() {
+		var ok bool
+		if pos, ok = s.feedASCII(chunk, pos); !ok {
+			return s.rejectTrailing(int64(len(chunk) - pos))
+		}
+	}
+
+	// Printable ASCII needs no more than the bookkeeping of acceptPrintable unless the options
+	// examine every rune; checked once per chunk, as the snippet only fills up.
+	inline, table := s.inlineASCII(), s.opts.allowedASCII
+	for pos < len(chunk) {
+		if b := chunk[pos]; b < utf8.RuneSelf {
+			if inline && b >= ' ' && b != 0x7f && table[b] {
+				s.acceptPrintable(b)
+				pos++
+				continue
+			}
+			if !s.accept(rune(b)) {
+				return s.rejectTrailing(int64(len(chunk) - pos))
+			}
+			pos++
+			continue
+		}
+
+		s.sawHighByte = true
+		if !utf8.FullRune(chunk[pos:]) {
+			s.carryLen = copy(s.carry[:], chunk[pos:])
+			break
+		}
+
+		r, size := utf8.DecodeRune(chunk[pos:])
+		if r == utf8.RuneError && size == 1 {
+			if !s.invalid() {
+				return s.rejectTrailing(int64(len(chunk) - pos))
+			}
+			pos++
+			continue
+		}
+		if !s.accept(r) {
+			return s.rejectTrailing(int64(len(chunk) - pos))
+		}
+		pos += size
+	}
+	return true
+}
+
+// asciiFastPath reports whether the next bytes may be validated by feedASCII.
+func (s *scanner) asciiFastPath() bool {
+	return s.opts.ASCIIFastPath && !s.sawHighByte && s.opts.allowedASCII != nil && !s.utf8Only &&
+		!s.opts.RecognizeANSIEscapes && !s.opts.DetectIndentation && !s.opts.DetectStructuredIndent && !s.opts.DetectScript && !s.opts.DetectDelimited &&
+		!s.opts.DetectTemplateMarkers && !s.opts.CountWords && !s.opts.NormalizeForMetrics && s.opts.MinPrintableRun == 0 &&
+		s.opts.ControlDensityWindow == 0 &&
+		s.snippetRunes >= s.opts.SnippetRunes
+}
+
+// feedASCII validates chunk from pos for as long as it is ASCII, recording printable characters
+// inline and passing control characters and disallowed runes to accept. It returns the position
+// of the first byte outside ASCII, or of the rejected byte, and whether the content is still
+// plaintext.
+func (s *scanner) feedASCII(chunk []byte, pos int) (int, bool) {
+	table := s.opts.allowedASCII
+	for ; pos < len(chunk); pos++ {
+		b := chunk[pos]
+		if b >= utf8.RuneSelf {
+			s.sawHighByte = true
+			return pos, true
+		}
+		if b < ' ' || b == 0x7f || !table[b] {
+			if !s.accept(rune(b)) {
+				return pos, false
+			}
+			continue
+		}
+		s.acceptPrintable(b)
+	}
+	return pos, true
+}
+
+// inlineASCII reports whether printable ASCII characters can be recorded by acceptPrintable
+// instead of accept, because no option examines every rune.
+func (s *scanner) inlineASCII() bool {
+	return s.opts.allowedASCII != nil && !s.utf8Only && !s.opts.RecognizeANSIEscapes &&
+		!s.opts.runeMetadata && s.opts.ControlDensityWindow == 0 && s.snippetRunes >= s.opts.SnippetRunes
+}
+
+// acceptPrintable is the equivalent of accept for a printable ASCII character allowed by the
+// options, when no option examines every rune.
+func (s *scanner) acceptPrintable(b byte) {
+	s.invalidRun = 0
+	s.runes++
+	s.lastSeparator = false
+	s.lineOpen = true
+	s.afterCR = false
+	s.trailingSpace = b == ' '
+	if b != ' ' {
+		s.sawNonSpace = true
+	}
+}
+
+// clean reports whether everything fed so far is plaintext without any tolerated issue, so that
+// the content can be accepted before its end.
+func (s *scanner) clean() bool {
+	return !s.failed && !s.trailing && s.invalidBytes == 0 && s.nonPrintable == 0 &&
+		s.nonGraphic == 0 && s.ansi == ansiNone
+}
+
+// finish marks the end of the content and reports whether the content fed to the scanner
+// was plaintext. The bytes of a rune still incomplete at the end of the content are invalid UTF-8.
+func (s *scanner) finish() bool {
+	for ; s.carryLen > 0 && !s.failed && !s.trailing; s.carryLen-- {
+		if !s.invalid() {
+			s.rejectTrailing(int64(s.carryLen))
+		}
+	}
+	// An escape sequence left unterminated at the end of the content is malformed.
+	if s.ansi != ansiNone && !s.trailing {
+		s.failed = true
+	}
+	if s.opts.PrintableThreshold > 0 && s.printableRatio() < s.opts.PrintableThreshold {
+		s.failed = true
+	}
+	if s.opts.ControlDensityWindow > 0 && s.density.exceeds(s.opts.ControlDensityMaxRatio, true) {
+		s.failed = true
+	}
+	if s.opts.MinPrintableRun > 0 && s.longestRun < s.opts.MinPrintableRun && s.longestRun < s.runes+s.invalidBytes {
+		s.failed = true
+	}
+	if s.opts.RejectUnnormalized && !s.nfc.normal() {
+		s.failed = true
+	}
+	if s.opts.MinPrintableRatio > 0 && s.runes > 0 && float64(s.runes-s.nonGraphic)/float64(s.runes) < s.opts.MinPrintableRatio {
+		s.failed = true
+	}
+	return !s.failed
+}
+
+// printableRatio returns the fraction of the examined runes and invalid bytes that were
+// printable. Content with nothing examined is entirely printable.
+func (s *scanner) printableRatio() float64 {
+	total := s.runes + s.invalidBytes
+	if total == 0 {
+		return 1
+	}
+	return float64(s.runes-s.nonPrintable) / float64(total)
+}
+
+// result returns the Result for the content fed to the scanner.
+func (s *scanner) result() Result {
+	enc := s.detection.encoding
+	if enc == Unknown {
+		enc = UTF8
+	}
+	// Content rejected while it was fed was not examined to its end.
+	stopped := s.failed
+	isPlaintext := s.finish()
+	res := Result{
+		IsPlaintext:    isPlaintext,
+		Classification: Binary,
+		WhitespaceOnly: isPlaintext && !s.sawNonSpace,
+		Encoding:       enc,
+		Confidence:     s.detection.confidence,
+		Endianness:     endianness(enc),
+		RuneCount:      s.runes,
+		Lines:          s.lines,
+		InvalidBytes:   s.invalidBytes,
+		PrintableRatio: s.printableRatio(),
+		FinalNewline:   !stopped && (s.lastByte == '\n' || s.lastSeparator && !s.trailing),
+		ContainsANSI:   s.sawANSI,
+	}
+	if isPlaintext && s.trailing {
+		res.TrailingIgnored = s.trailingBytes
+	}
+	switch {
+	case isStructuredDocument(s.head[:s.headLen]):
+		res.Classification = StructuredDocument
+	case isPlaintext:
+		res.Classification = PlainText
+	}
+	if s.lineOpen {
+		res.Lines++
+	}
+	res.TrailingWhitespaceCount = s.trailingWSCount
+	res.TrailingWhitespaceLines = s.trailingWSLines
+	if s.opts.TrackTrailingWhitespace && s.lineOpen && s.trailingSpace {
+		// The final partial line ends at the end of the content.
+		res.TrailingWhitespaceCount++
+		if len(res.TrailingWhitespaceLines) < maxTrailingWhitespaceLines {
+			res.TrailingWhitespaceLines = append(slices.Clip(res.TrailingWhitespaceLines), res.Lines)
+		}
+	}
+	if s.opts.DetectIndentation {
+		res.IndentStyle, res.IndentWidth = s.indent.style()
+	}
+	if s.opts.DetectScript {
+		res.Script = s.scripts.dominant()
+	}
+	if s.opts.RequireNFC {
+		res.NotNormalized = !s.nfc.normal()
+	}
+	if s.opts.DetectStructuredIndent && isPlaintext {
+		style, width := s.indent.style()
+		res.StructuredIndentLikely = style == Spaces && (width == 2 || width == 4) &&
+			s.indent.consistentSteps(width) && s.keys.likely()
+	}
+	if s.opts.DetectTemplateMarkers && isPlaintext {
+		res.ContainsTemplateMarkers = s.templates.found
+	}
+	if s.opts.DetectByteWidth && isPlaintext {
+		res.MaxByteWidth = byteWidth(s.maxRune)
+	}
+	if s.opts.DetectDelimited && isPlaintext {
+		res.Delimiter, res.Columns = s.delimited.delimiter()
+		res.Delimited = res.Columns > 0
+	}
+	if !isPlaintext {
+		res.RejectedFormatChar = s.formatChar
+	}
+	if s.opts.NormalizeForMetrics {
+		res.LineEndings = s.lineEndings
+	}
+	if s.opts.CountWords && isPlaintext {
+		res.Words = s.words
+	}
+	if isPlaintext {
+		res.Snippet = string(s.snippet)
+		// ASCII content is already UTF-8 in encodings that keep ASCII as is, such as Latin-1.
+		res.NeedsReencoding = enc != UTF8 && (s.sawHighByte || !isASCIICompatible(enc))
+	}
+	return res
+}
+
+// incompleteResult returns the Result for a scan cut short by a read error.
+// A rune left incomplete by the interruption is not held against the content.
+func (s *scanner) incompleteResult() Result {
+	s.carryLen = 0
+	res := s.result()
+	res.Incomplete = true
+	return res
+}
+
+// isFormatChar reports whether r is an invisible format character in Unicode category Cf.
+func isFormatChar(r rune) bool {
+	return unicode.Is(unicode.Cf, r)
+}
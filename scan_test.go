@@ -0,0 +1,221 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestScannerSplitRunes(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"emoji", []byte("Hello 👋 World! 🌍\n"), true},
+		{"chinese", []byte("你好，世界！\n"), true},
+		{"truncated rune", []byte("Hello \xf0\x9f\x91"), false},
+		{"invalid continuation", []byte("Hello \xe4\x41\x41"), false},
+		{"control character", []byte("Hello\x07"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Feed the content one byte at a time so every multibyte rune is split across chunks.
+			var s scanner
+			for i := range tt.content {
+				s.feed(tt.content[i : i+1])
+			}
+			if res := s.finish(); res != tt.expected {
+				t.Errorf("scanner.finish() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScannerInvalidBytesAcrossChunks(t *testing.T) {
+	tests := [][]byte{
+		[]byte("Hello \xe2\x82 World"),
+		[]byte("\xf0\x9f\x91Hello"),
+		[]byte("emoji 👋 then \xff\xfe and 你好"),
+		[]byte("ends with a truncated rune \xe4\xbd"),
+	}
+
+	for _, content := range tests {
+		whole := scanner{opts: Options{MaxInvalidBytes: 100}}
+		whole.feed(content)
+		want := whole.result()
+
+		split := scanner{opts: Options{MaxInvalidBytes: 100}}
+		for i := range content {
+			split.feed(content[i : i+1])
+		}
+		got := split.result()
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("byte-by-byte result for %q = %+v, want %+v", content, got, want)
+		}
+	}
+}
+
+func TestASCIITable(t *testing.T) {
+	optionSets := [][]Option{
+		nil,
+		{WithAllowedControlChars('\f', '\v', 0)},
+		{WithUnicodeCategory(unicode.L, unicode.Zs)},
+		{WithUnicodeCategory(unicode.N), WithAllowedControlChars('\a')},
+		{WithRejectAstral(), WithRejectInteriorBOM()},
+	}
+	contents := []string{
+		"Hello, World!\n",
+		"page one\fpage two\v",
+		"letters only",
+		"12345",
+		"ding\a",
+		"\x00\x01\x02",
+	}
+
+	for _, opts := range optionSets {
+		o := buildOptions(opts)
+		if o.allowedASCII == nil {
+			t.Fatalf("buildOptions() did not build the ASCII table")
+		}
+		chain := o
+		chain.allowedASCII = nil
+
+		for r := range rune(utf8.RuneSelf) {
+			table, evaluated := (&scanner{opts: o}).allowed(r), (&scanner{opts: chain}).allowed(r)
+			if table != evaluated {
+				t.Errorf("allowed(%q) with table = %v, without = %v", r, table, evaluated)
+			}
+		}
+		for _, content := range contents {
+			got, _ := checkBytes([]byte(content), o)
+			want, _ := checkBytes([]byte(content), chain)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("checkBytes(%q) with table = %+v, without = %+v", content, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkASCIIPolicy(b *testing.B) {
+	content := bytes.Repeat([]byte("The quick brown fox\tjumps over the lazy dog.\f\n"), 64*1024)
+	o := buildOptions([]Option{WithAllowedControlChars('\f', '\v', '\a', '\b')})
+	chain := o
+	chain.allowedASCII = nil
+
+	for _, bm := range []struct {
+		name string
+		opts Options
+	}{
+		{"comparison chain", chain},
+		{"table", o},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for b.Loop() {
+				s := scanner{opts: bm.opts}
+				if !s.feed(content) {
+					b.Fatal("content rejected")
+				}
+			}
+		})
+	}
+}
+
+func TestASCIIFastPath(t *testing.T) {
+	ascii := strings.Repeat("The quick brown fox\tjumps over the lazy dog.\r\n", 20000)
+
+	tests := []struct {
+		name     string
+		content  string
+		opts     []Option
+		expected bool
+	}{
+		{"ASCII", ascii, nil, true},
+		{"late multibyte character", ascii + "café 👋\n", nil, true},
+		{"late invalid byte", ascii + "caf\xe9\n", nil, false},
+		{"late truncated rune", ascii + "\xf0\x9f\x91", nil, false},
+		{"late disallowed rune after multibyte", ascii + "é\x07", nil, false},
+		{"control character", ascii + "\x07" + ascii, nil, false},
+		{"allowed control character", ascii + "\f" + ascii, []Option{WithAllowedControlChars('\f')}, true},
+		{"threshold", ascii + "\x07\n", []Option{WithPrintableThreshold(0.9)}, true},
+		{"trailing tolerance", ascii + "\x00\x01", []Option{WithTrailingToleranceBytes(4)}, true},
+		{"whitespace only", " \t\n  \n", nil, true},
+		{"final partial line with trailing space", "line\nlast ", []Option{WithTrackTrailingWhitespace()}, true},
+		{"ANSI escapes", "\x1b[31mred\x1b[0m\n", []Option{WithRecognizeANSIEscapes()}, true},
+		{"snippet", ascii, []Option{WithSnippet(10)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := Check(strings.NewReader(tt.content), tt.opts...)
+			if err != nil {
+				t.Fatalf("Check() error: %v", err)
+			}
+			got, err := Check(strings.NewReader(tt.content), append(tt.opts, WithASCIIFastPath())...)
+			if err != nil {
+				t.Fatalf("Check() with fast path error: %v", err)
+			}
+			if got.IsPlaintext != tt.expected {
+				t.Errorf("Check().IsPlaintext = %v, want %v", got.IsPlaintext, tt.expected)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Check() with fast path = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestInlineASCII(t *testing.T) {
+	contents := []string{
+		"The quick brown fox\tjumps over the lazy dog.\r\n",
+		"line\nlast ",
+		" \t\n  \n",
+		"caf\u00e9 \U0001F44B\n",
+		"text\x07more",
+		"caf\xe9\n",
+		"\xf0\x9f\x91",
+		"\uFFFDa\uFFFDb\uFFFD",
+		"",
+	}
+
+	for _, content := range contents {
+		// Marking the options as examining every rune sends printable ASCII through accept.
+		inline := buildOptions([]Option{WithTrackTrailingWhitespace(), WithMaxConsecutiveInvalid(1)})
+		perRune := inline
+		perRune.runeMetadata = true
+
+		want, _ := checkBytes([]byte(content), perRune)
+		got, _ := checkBytes([]byte(content), inline)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("inline result for %q = %+v, want %+v", content, got, want)
+		}
+	}
+}
+
+func BenchmarkASCIIFastPath(b *testing.B) {
+	content := bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog.\n"), 10<<20/45)
+
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"fast path", []Option{WithASCIIFastPath()}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for b.Loop() {
+				res, err := Check(bytes.NewReader(content), bm.opts...)
+				if err != nil || !res.IsPlaintext {
+					b.Fatalf("Check() = %+v, %v", res, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package isplaintextfile
+
+import "fmt"
+
+// ScanState is the incremental scanner behind the package's checks, for callers that run their
+// own streaming loop over any transport. Content is fed in chunks of any size: a rune split
+// between chunks is validated once complete, and the metadata enabled by the options is kept
+// across chunks. A ScanState is not safe for concurrent use.
+type ScanState struct {
+	s scanner
+}
+
+// NewScanState returns a ScanState that classifies UTF-8 content according to the options. As the
+// caller does the reading, options that decode content, WithEncoding other than UTF8,
+// WithCharsetDetector, and WithRespectDeclaredEncoding, are not supported and return an error
+// wrapping ErrUnsupportedEncoding; decode content before feeding it instead. Options that control
+// reading, such as WithTimeBudget, WithStopCondition, and WithProgress, have no effect.
+func NewScanState(opts ...Option) (*ScanState, error) {
+	o := buildOptions(opts)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if (o.Encoding != Unknown && o.Encoding != UTF8) || o.CharsetDetector != nil || o.RespectDeclaredEncoding {
+		return nil, fmt.Errorf("%w: ScanState only validates UTF-8", ErrUnsupportedEncoding)
+	}
+	return &ScanState{s: scanner{opts: o}}, nil
+}
+
+// Feed validates the next chunk of content and reports whether everything fed so far may still be
+// plaintext. Once it reports false the content is rejected, and later chunks are ignored.
+func (st *ScanState) Feed(chunk []byte) (ok bool) {
+	return st.s.feed(chunk)
+}
+
+// Done marks the end of the content and returns its Result. The bytes of a rune left incomplete
+// by the last chunk are invalid. Done must be called once, after the last call to Feed.
+func (st *ScanState) Done() Result {
+	return st.s.result()
+}
@@ -0,0 +1,86 @@
+package isplaintextfile
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScanState(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunks   []string
+		expected bool
+		runes    int64
+	}{
+		{"emoji split across three feeds", []string{"Hi \xf0", "\x9f", "\x91\x8b!\n"}, true, 6},
+		{"one byte per feed", []string{"o", "k", "\xc3", "\xa9"}, true, 3},
+		{"truncated rune at the end", []string{"Hi \xf0", "\x9f"}, false, 3},
+		{"control character", []string{"Hi", "\x07"}, false, 2},
+		{"no content", nil, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := NewScanState(WithDetectScript())
+			if err != nil {
+				t.Fatalf("NewScanState() error: %v", err)
+			}
+			for _, chunk := range tt.chunks {
+				st.Feed([]byte(chunk))
+			}
+
+			res := st.Done()
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("Done().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+			if res.RuneCount != tt.runes {
+				t.Errorf("Done().RuneCount = %d, want %d", res.RuneCount, tt.runes)
+			}
+		})
+	}
+}
+
+func TestScanStateFeedRejects(t *testing.T) {
+	st, err := NewScanState()
+	if err != nil {
+		t.Fatalf("NewScanState() error: %v", err)
+	}
+	if !st.Feed([]byte("text")) {
+		t.Errorf("Feed() = false, want true")
+	}
+	if st.Feed([]byte{0x00}) {
+		t.Errorf("Feed() with NUL = true, want false")
+	}
+	if st.Feed([]byte("more text")) {
+		t.Errorf("Feed() after rejection = true, want false")
+	}
+}
+
+func TestScanStateMetadata(t *testing.T) {
+	st, err := NewScanState(WithDetectScript(), WithTrackTrailingWhitespace(), WithSnippet(3))
+	if err != nil {
+		t.Fatalf("NewScanState() error: %v", err)
+	}
+	for _, chunk := range []string{"Привет ", "\n", "мир\n"} {
+		st.Feed([]byte(chunk))
+	}
+
+	res := st.Done()
+	if res.Script != "Cyrillic" || res.Lines != 2 || res.TrailingWhitespaceCount != 1 || res.Snippet != "При" {
+		t.Errorf("Done() = %+v, want Cyrillic text of 2 lines, one with trailing whitespace", res)
+	}
+}
+
+func TestNewScanStateOptions(t *testing.T) {
+	if _, err := NewScanState(WithEncoding(Latin1)); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("NewScanState() error = %v, want %v", err, ErrUnsupportedEncoding)
+	}
+	if _, err := NewScanState(WithEncoding(UTF8)); err != nil {
+		t.Errorf("NewScanState() error: %v", err)
+	}
+
+	var optErr *OptionError
+	if _, err := NewScanState(WithNULFastReject(), WithAllowedControlChars(0)); !errors.As(err, &optErr) {
+		t.Errorf("NewScanState() error = %v, want *OptionError", err)
+	}
+}
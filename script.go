@@ -0,0 +1,62 @@
+package isplaintextfile
+
+import (
+	"unicode"
+)
+
+// scriptSampleSize is the number of letters sampled to find the dominant script.
+const scriptSampleSize = 4096
+
+// commonScripts are checked before the full list of Unicode scripts, as most text is in one of them.
+var commonScripts = []string{
+	"Latin", "Han", "Cyrillic", "Arabic", "Greek", "Hebrew", "Hiragana", "Katakana", "Hangul",
+	"Devanagari", "Thai",
+}
+
+// scriptStats counts the letters of each script in a sample of the content.
+type scriptStats struct {
+	sampled int
+	counts  map[string]int
+}
+
+// next counts the script of the next rune if it is a letter and the sample is not yet full.
+func (st *scriptStats) next(r rune) {
+	if st.sampled >= scriptSampleSize || !unicode.IsLetter(r) {
+		return
+	}
+	st.sampled++
+	if st.counts == nil {
+		st.counts = make(map[string]int)
+	}
+	st.counts[scriptOf(r)]++
+}
+
+// scriptOf returns the name of the Unicode script of the rune, or an empty string if it has none.
+func scriptOf(r rune) string {
+	if r < 0x80 {
+		return "Latin"
+	}
+	for _, name := range commonScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// dominant returns the script with the most sampled letters, or an empty string if no letter was
+// sampled. Ties go to the name that sorts first.
+func (st *scriptStats) dominant() string {
+	best, most := "", 0
+	for name, n := range st.counts {
+		if name != "" && (n > most || n == most && name < best) {
+			best, most = name, n
+		}
+	}
+	return best
+}
@@ -0,0 +1,97 @@
+package isplaintextfile
+
+import "unicode"
+
+// minKeyLines is the fewest key-colon lines for content to look structured.
+const minKeyLines = 2
+
+// keyLineStats counts the lines that look like a YAML mapping key, "key: value" or "key:", or a
+// Python block header, ending in a colon.
+type keyLineStats struct {
+	// state is how far into the current line the scan is; last is its last rune that is not
+	// whitespace and content reports that it has one.
+	state   keyState
+	last    rune
+	content bool
+	matched bool
+
+	lines, keyLines int
+}
+
+// keyState is the position of a scan within a line, relative to a leading key.
+type keyState int
+
+const (
+	keyIndent keyState = iota // in the leading whitespace
+	keyName                   // in a leading run of key characters
+	keyColon                  // right after a colon ending the key
+	keyRest                   // anywhere else
+)
+
+// next updates the statistics for the next rune. lineBreak reports that the rune ends a line.
+func (st *keyLineStats) next(r rune, lineBreak bool) {
+	if lineBreak {
+		st.endLine()
+		return
+	}
+	space := r == ' ' || r == '\t'
+	switch st.state {
+	case keyIndent:
+		if space {
+			return
+		}
+		st.state = keyRest
+		if isKeyRune(r) {
+			st.state = keyName
+		}
+	case keyName:
+		switch {
+		case r == ':':
+			st.state = keyColon
+		case !isKeyRune(r):
+			st.state = keyRest
+		}
+	case keyColon:
+		st.matched = st.matched || space
+		st.state = keyRest
+	}
+	if !space {
+		st.last = r
+		st.content = true
+	}
+}
+
+// endLine counts the line that just ended, unless it was empty.
+func (st *keyLineStats) endLine() {
+	if st.content {
+		st.lines++
+		if st.matched || st.state == keyColon || st.last == ':' {
+			st.keyLines++
+		}
+	}
+	*st = keyLineStats{lines: st.lines, keyLines: st.keyLines}
+}
+
+// likely reports whether enough lines, at least a quarter of them, look like keys. A final line
+// without a line break is included.
+func (st *keyLineStats) likely() bool {
+	final := *st
+	final.endLine()
+	return final.keyLines >= minKeyLines && final.keyLines*4 >= final.lines
+}
+
+// isKeyRune reports whether r can be part of a mapping key or an identifier.
+func isKeyRune(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// consistentSteps reports whether every change of indentation between lines indented with spaces
+// is a multiple of width.
+func (st *indentStats) consistentSteps(width int) bool {
+	for step, n := range st.steps {
+		if n > 0 && step%width != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+// teeReader forwards everything read from r to w and to a concurrent check of the content.
+type teeReader struct {
+	r    io.Reader
+	w    io.Writer
+	pw   *io.PipeWriter
+	done chan struct{}
+	res  Result
+	err  error
+}
+
+// TeeReader returns a reader that writes everything read from r to w, like io.TeeReader,
+// while classifying the content in the same pass. Once the caller has finished reading,
+// the returned function reports whether the content read was plaintext. The function must
+// be called exactly once, even if reading stops early, to release the classification.
+func TeeReader(r io.Reader, w io.Writer, opts ...Option) (io.Reader, func() (bool, error)) {
+	pr, pw := io.Pipe()
+	t := &teeReader{r: r, w: w, pw: pw, done: make(chan struct{})}
+	o := buildOptions(opts)
+
+	go func() {
+		defer close(t.done)
+		t.res, t.err = check(pr, nil, o)
+		// Keep accepting content after an early verdict so reads through the tee never block.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	return t, t.finish
+}
+
+// Read implements io.Reader.
+func (t *teeReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if _, werr := t.w.Write(p[:n]); werr != nil {
+			return n, werr
+		}
+		_, _ = t.pw.Write(p[:n])
+	}
+	if err != nil && err != io.EOF {
+		t.pw.CloseWithError(err)
+	}
+	return n, err
+}
+
+// finish ends the classification and returns its verdict.
+func (t *teeReader) finish() (bool, error) {
+	t.pw.Close()
+	<-t.done
+	return t.res.IsPlaintext, t.err
+}
+
+// ErrTeeBufferExceeded is returned by ReaderTee when classifying would buffer more than the configured maximum.
+var ErrTeeBufferExceeded = errors.New("tee buffer limit exceeded")
+
+// recordingReader keeps a copy of everything read through it, up to max bytes when max is positive.
+type recordingReader struct {
+	r   io.Reader
+	buf bytes.Buffer
+	max int64
+}
+
+// Read implements io.Reader. Reads are shortened so the recording never exceeds its maximum by
+// more than one byte, read to tell whether the content ends exactly at the maximum. If that byte
+// is there, it is recorded so nothing is lost, but ErrTeeBufferExceeded is returned in its place.
+func (rr *recordingReader) Read(p []byte) (int, error) {
+	if rr.max > 0 {
+		remaining := rr.max + 1 - int64(rr.buf.Len())
+		if remaining <= 0 {
+			return 0, ErrTeeBufferExceeded
+		}
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := rr.r.Read(p)
+	rr.buf.Write(p[:n])
+	if rr.max > 0 && int64(rr.buf.Len()) > rr.max {
+		return n - 1, ErrTeeBufferExceeded
+	}
+	return n, err
+}
+
+// ReaderTee classifies the content of the reader and returns a reader that yields the full
+// content from the start, so the stream can still be consumed after classification. The bytes
+// consumed by the classification are buffered in memory; use WithMaxTeeBuffer to bound them.
+// The returned reader is valid even when an error is returned.
+func ReaderTee(reader io.Reader, opts ...Option) (Result, io.Reader, error) {
+	o := buildOptions(opts)
+	rr := &recordingReader{r: reader, max: o.MaxTeeBuffer}
+
+	res, err := check(rr, nil, o)
+	return res, io.MultiReader(&rr.buf, reader), err
+}
+
+// CopyIfText copies src to dst only if its content is plaintext, returning the number of bytes
+// written and the verdict. Nothing is written to dst when the content is not plaintext.
+//
+// A src that can seek, such as an *os.File, is read twice: it is classified, rewound to where it
+// was, and then copied, so memory use stays constant. Any other src is buffered in memory while
+// it is classified, as ReaderTee does; use WithMaxTeeBuffer to bound the buffer, in which case
+// ErrTeeBufferExceeded is returned, with nothing written, if classification needs more.
+func CopyIfText(dst io.Writer, src io.Reader, opts ...Option) (written int64, isText bool, err error) {
+	o := buildOptions(opts)
+
+	if seeker, ok := src.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			res, err := check(src, nil, o)
+			if err != nil || !res.IsPlaintext {
+				return 0, false, err
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return 0, true, err
+			}
+			written, err = io.Copy(dst, src)
+			return written, true, err
+		}
+	}
+
+	rr := &recordingReader{r: src, max: o.MaxTeeBuffer}
+	res, err := check(rr, nil, o)
+	if err != nil || !res.IsPlaintext {
+		return 0, false, err
+	}
+	written, err = io.Copy(dst, io.MultiReader(&rr.buf, src))
+	return written, true, err
+}
+
+// StdinTee reads standard input to the end, writing all of it to dst while classifying it, and
+// reports whether it was plaintext, so a command-line filter can pass its input through while
+// deciding whether it is text. The content streams through as it is read, as with TeeReader, so
+// nothing is buffered however long the input is. The content is forwarded whatever the verdict.
+func StdinTee(dst io.Writer) (bool, error) {
+	tee, finish := TeeReader(os.Stdin, dst)
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		_, _ = finish()
+		return false, err
+	}
+	return finish()
+}
@@ -0,0 +1,245 @@
+package isplaintextfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestTeeReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"plain ASCII text", []byte("Hello, World!\n"), true},
+		{"binary content", []byte{0x00, 0x01, 0x02, 0x03}, false},
+		{"large text file", bytes.Repeat([]byte("Large plain text content\n"), 10000), true},
+		{"binary early in large content", append([]byte{0x00}, bytes.Repeat([]byte("text\n"), 50000)...), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			reader, verdict := TeeReader(bytes.NewReader(tt.content), &out)
+
+			if _, err := io.Copy(io.Discard, reader); err != nil {
+				t.Fatalf("io.Copy() error: %v", err)
+			}
+
+			res, err := verdict()
+			if err != nil {
+				t.Errorf("TeeReader() verdict error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("TeeReader() verdict = %v, want %v", res, tt.expected)
+			}
+			if !bytes.Equal(out.Bytes(), tt.content) {
+				t.Errorf("TeeReader() wrote %d bytes, want %d", out.Len(), len(tt.content))
+			}
+		})
+	}
+}
+
+func TestReaderTee(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"plain ASCII text", []byte("Hello, World!\n"), true},
+		{"binary content", []byte{0x00, 0x01, 0x02, 0x03}, false},
+		{"large text file", bytes.Repeat([]byte("Large plain text content\n"), 10000), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, replay, err := ReaderTee(bytes.NewReader(tt.content))
+			if err != nil {
+				t.Errorf("ReaderTee() error: %v", err)
+			}
+			if res.IsPlaintext != tt.expected {
+				t.Errorf("ReaderTee().IsPlaintext = %v, want %v", res.IsPlaintext, tt.expected)
+			}
+
+			replayed, err := io.ReadAll(replay)
+			if err != nil {
+				t.Fatalf("io.ReadAll() error: %v", err)
+			}
+			if !bytes.Equal(replayed, tt.content) {
+				t.Errorf("ReaderTee() replayed %d bytes, want %d", len(replayed), len(tt.content))
+			}
+		})
+	}
+}
+
+func TestWithMaxTeeBuffer(t *testing.T) {
+	content := bytes.Repeat([]byte("Large plain text content\n"), 10000)
+
+	_, replay, err := ReaderTee(bytes.NewReader(content), WithMaxTeeBuffer(1024))
+	if !errors.Is(err, ErrTeeBufferExceeded) {
+		t.Errorf("ReaderTee() error = %v, want %v", err, ErrTeeBufferExceeded)
+	}
+
+	// Nothing is lost when the limit is hit.
+	replayed, err := io.ReadAll(replay)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error: %v", err)
+	}
+	if !bytes.Equal(replayed, content) {
+		t.Errorf("ReaderTee() replayed %d bytes, want %d", len(replayed), len(content))
+	}
+
+	// Content within the limit is classified normally.
+	res, _, err := ReaderTee(bytes.NewReader(content[:512]), WithMaxTeeBuffer(1024))
+	if err != nil {
+		t.Errorf("ReaderTee() error: %v", err)
+	}
+	if !res.IsPlaintext {
+		t.Errorf("ReaderTee().IsPlaintext = %v, want true", res.IsPlaintext)
+	}
+
+	// Content of exactly the limit fits; one more byte does not.
+	for _, size := range []int{1024, 1025} {
+		exceeded := size > 1024
+		res, replay, err := ReaderTee(bytes.NewReader(content[:size]), WithMaxTeeBuffer(1024))
+		if errors.Is(err, ErrTeeBufferExceeded) != exceeded || !exceeded && (err != nil || !res.IsPlaintext) {
+			t.Errorf("ReaderTee() of %d bytes = %+v, %v", size, res, err)
+		}
+		if replayed, _ := io.ReadAll(replay); !bytes.Equal(replayed, content[:size]) {
+			t.Errorf("ReaderTee() of %d bytes replayed %d bytes", size, len(replayed))
+		}
+
+		var dst bytes.Buffer
+		written, isText, err := CopyIfText(&dst, opaqueReader{bytes.NewReader(content[:size])}, WithMaxTeeBuffer(1024))
+		if errors.Is(err, ErrTeeBufferExceeded) != exceeded || !exceeded && (!isText || written != int64(size)) {
+			t.Errorf("CopyIfText() of %d bytes = %d, %v, %v", size, written, isText, err)
+		}
+
+		if _, err := UTF8Reader(bytes.NewReader(content[:size]), WithMaxTeeBuffer(1024)); errors.Is(err, ErrTeeBufferExceeded) != exceeded {
+			t.Errorf("UTF8Reader() of %d bytes error = %v", size, err)
+		}
+	}
+
+	// Best effort does not turn the limit into a verdict, which would miss a NUL past it.
+	withNUL := append(append([]byte{}, content[:1024]...), 0x00)
+	if _, _, err := ReaderTee(bytes.NewReader(withNUL), WithMaxTeeBuffer(1024), WithBestEffortOnError()); !errors.Is(err, ErrTeeBufferExceeded) {
+		t.Errorf("ReaderTee() with best effort error = %v, want %v", err, ErrTeeBufferExceeded)
+	}
+}
+
+func TestCopyIfText(t *testing.T) {
+	text := bytes.Repeat([]byte("Large plain text content\n"), 10000)
+	binary := append(append([]byte{}, text...), 0x00, 0x01, 0x02)
+
+	tests := []struct {
+		name     string
+		content  []byte
+		seekable bool
+		expected bool
+	}{
+		{"seekable text", text, true, true},
+		{"seekable binary", binary, true, false},
+		{"stream text", text, false, true},
+		{"stream binary", binary, false, false},
+		{"empty", nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var src io.Reader = bytes.NewReader(tt.content)
+			if !tt.seekable {
+				src = opaqueReader{src}
+			}
+
+			var dst bytes.Buffer
+			written, isText, err := CopyIfText(&dst, src)
+			if err != nil {
+				t.Errorf("CopyIfText() error: %v", err)
+			}
+			if isText != tt.expected {
+				t.Errorf("CopyIfText() isText = %v, want %v", isText, tt.expected)
+			}
+
+			want := tt.content
+			if !tt.expected {
+				want = nil
+			}
+			if written != int64(len(want)) || !bytes.Equal(dst.Bytes(), want) {
+				t.Errorf("CopyIfText() wrote %d bytes (%d reported), want %d", dst.Len(), written, len(want))
+			}
+		})
+	}
+}
+
+func TestCopyIfTextRewindsToStart(t *testing.T) {
+	src := bytes.NewReader([]byte("header\nbody\n"))
+	if _, err := src.Seek(7, io.SeekStart); err != nil {
+		t.Fatalf("Seek() error: %v", err)
+	}
+
+	var dst bytes.Buffer
+	if _, isText, err := CopyIfText(&dst, src); err != nil || !isText {
+		t.Errorf("CopyIfText() = %v, %v, want true", isText, err)
+	}
+	if dst.String() != "body\n" {
+		t.Errorf("CopyIfText() wrote %q, want %q", dst.String(), "body\n")
+	}
+}
+
+func TestCopyIfTextBufferLimit(t *testing.T) {
+	content := bytes.Repeat([]byte("Large plain text content\n"), 10000)
+
+	var dst bytes.Buffer
+	written, _, err := CopyIfText(&dst, opaqueReader{bytes.NewReader(content)}, WithMaxTeeBuffer(1024))
+	if !errors.Is(err, ErrTeeBufferExceeded) {
+		t.Errorf("CopyIfText() error = %v, want %v", err, ErrTeeBufferExceeded)
+	}
+	if written != 0 || dst.Len() != 0 {
+		t.Errorf("CopyIfText() wrote %d bytes, want 0", dst.Len())
+	}
+}
+
+func TestStdinTee(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected bool
+	}{
+		{"text", bytes.Repeat([]byte("piped line of text\n"), 10000), true},
+		{"binary", append(bytes.Repeat([]byte("text then binary\n"), 10000), 0x00, 0x01), false},
+		{"empty", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("os.Pipe() error: %v", err)
+			}
+			defer r.Close()
+			stdin := os.Stdin
+			os.Stdin = r
+			defer func() { os.Stdin = stdin }()
+
+			go func() {
+				_, _ = w.Write(tt.content)
+				w.Close()
+			}()
+
+			var dst bytes.Buffer
+			res, err := StdinTee(&dst)
+			if err != nil {
+				t.Errorf("StdinTee() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("StdinTee() = %v, want %v", res, tt.expected)
+			}
+			if !bytes.Equal(dst.Bytes(), tt.content) {
+				t.Errorf("StdinTee() forwarded %d bytes, want %d", dst.Len(), len(tt.content))
+			}
+		})
+	}
+}
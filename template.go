@@ -0,0 +1,29 @@
+package isplaintextfile
+
+// templateMarkers looks for interpolation markers left unrendered in text: a "{{" followed later
+// by "}}", as in Go, Jinja, and Mustache templates, or a "${" followed later by "}", as in shell,
+// Terraform, and JavaScript template strings.
+type templateMarkers struct {
+	// prev is the previous rune, and open the first rune of the marker opened, '{' or '$', or 0.
+	prev  rune
+	open  rune
+	found bool
+}
+
+// next looks for markers in the next rune.
+func (t *templateMarkers) next(r rune) {
+	if t.found {
+		return
+	}
+	switch {
+	case t.open == 0 && r == '{' && (t.prev == '{' || t.prev == '$'):
+		t.open = t.prev
+		// The brace that opened the marker does not start another one.
+		r = 0
+	case t.open == '{' && r == '}' && t.prev == '}':
+		t.found = true
+	case t.open == '$' && r == '}':
+		t.found = true
+	}
+	t.prev = r
+}
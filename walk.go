@@ -0,0 +1,98 @@
+package isplaintextfile
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// walker classifies every regular file under a directory tree.
+type walker struct {
+	opts    Options
+	results map[string]Result
+
+	// visited holds the resolved paths of directories already walked, so that a symlink
+	// back to one of them is not followed again.
+	visited map[string]bool
+}
+
+// WalkDir classifies every regular file in the tree rooted at root and returns the results
+// keyed by path. Symbolic links are skipped unless WithFollowSymlinks(true) is given, in which
+// case linked files are classified and linked directories are walked, with each directory
+// walked at most once so that symlink cycles terminate.
+func WalkDir(root string, opts ...Option) (map[string]Result, error) {
+	w := &walker{
+		opts:    buildOptions(opts),
+		results: make(map[string]Result),
+		visited: make(map[string]bool),
+	}
+	if err := w.walk(root, root); err != nil {
+		return w.results, err
+	}
+	return w.results, nil
+}
+
+// walk walks the directory dir, reporting the paths it finds relative to display.
+func (w *walker) walk(dir, display string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.Join(display, rel)
+
+		switch {
+		case d.IsDir():
+			real, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return err
+			}
+			if w.visited[real] {
+				return filepath.SkipDir
+			}
+			w.visited[real] = true
+		case d.Type()&fs.ModeSymlink != 0:
+			if w.opts.FollowSymlinks {
+				return w.followSymlink(path, name)
+			}
+		case d.Type().IsRegular():
+			return w.checkFile(path, name)
+		}
+		return nil
+	})
+}
+
+// followSymlink classifies the file or walks the directory that the symlink at path points to.
+// Dangling symlinks and directories that have already been walked are skipped.
+func (w *walker) followSymlink(path, name string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if !info.IsDir() {
+		if info.Mode().IsRegular() {
+			return w.checkFile(path, name)
+		}
+		return nil
+	}
+
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil || w.visited[real] {
+		return nil
+	}
+	return w.walk(real, name)
+}
+
+// checkFile classifies the file at path and records the result under name.
+func (w *walker) checkFile(path, name string) error {
+	res, err := checkFile(path, w.opts)
+	if err != nil {
+		return err
+	}
+	w.results[name] = res
+	return nil
+}
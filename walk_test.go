@@ -0,0 +1,203 @@
+package isplaintextfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeTree creates the files in a temporary directory and returns its path.
+func writeTree(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	return root
+}
+
+// resultKeys returns the sorted paths of the results relative to root.
+func resultKeys(t *testing.T, root string, results map[string]Result) []string {
+	t.Helper()
+
+	var keys []string
+	for path := range results {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatalf("filepath.Rel() error: %v", err)
+		}
+		keys = append(keys, filepath.ToSlash(rel))
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestWalkDir(t *testing.T) {
+	root := writeTree(t, map[string][]byte{
+		"a.txt":     []byte("Hello, World!\n"),
+		"sub/b.bin": {0x00, 0x01, 0x02},
+		"sub/c.txt": []byte("你好，世界！\n"),
+	})
+
+	results, err := WalkDir(root)
+	if err != nil {
+		t.Fatalf("WalkDir() error: %v", err)
+	}
+	if keys := resultKeys(t, root, results); !slices.Equal(keys, []string{"a.txt", "sub/b.bin", "sub/c.txt"}) {
+		t.Errorf("WalkDir() paths = %v", keys)
+	}
+	for path, expected := range map[string]bool{"a.txt": true, "sub/b.bin": false, "sub/c.txt": true} {
+		if res := results[filepath.Join(root, path)]; res.IsPlaintext != expected {
+			t.Errorf("WalkDir() %s IsPlaintext = %v, want %v", path, res.IsPlaintext, expected)
+		}
+	}
+}
+
+func TestWalkDirSymlinkCycle(t *testing.T) {
+	root := writeTree(t, map[string][]byte{
+		"a.txt":     []byte("Hello, World!\n"),
+		"sub/b.txt": []byte("nested\n"),
+	})
+	outside := writeTree(t, map[string][]byte{"linked.txt": []byte("linked\n")})
+
+	// sub/loop points back at the root, and link.txt points at a file outside the tree.
+	if err := os.Symlink(root, filepath.Join(root, "sub", "loop")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "linked.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		follow   bool
+		expected []string
+	}{
+		{"skip symlinks", false, []string{"a.txt", "sub/b.txt"}},
+		{"follow symlinks", true, []string{"a.txt", "link.txt", "sub/b.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := WalkDir(root, WithFollowSymlinks(tt.follow))
+			if err != nil {
+				t.Fatalf("WalkDir() error: %v", err)
+			}
+			if keys := resultKeys(t, root, results); !slices.Equal(keys, tt.expected) {
+				t.Errorf("WalkDir() paths = %v, want %v", keys, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWalkDirForcedPatterns(t *testing.T) {
+	root := writeTree(t, map[string][]byte{
+		"app.js":           []byte("function main() {}\n"),
+		"app.min.js":       []byte("function main(){}\n"),
+		"data.bin":         {0x00, 0x01, 0x02},
+		"vendor/lib.js":    []byte("var lib = {};\n"),
+		"src/vendor.js":    []byte("var v = {};\n"),
+		"assets/blob.data": {0x00, 0xff},
+	})
+
+	results, err := WalkDir(root,
+		WithForceBinary([]string{"*.min.js", "vendor/*.js"}),
+		WithForceText([]string{"*.bin", "*.js"}))
+	if err != nil {
+		t.Fatalf("WalkDir() error: %v", err)
+	}
+
+	tests := []struct {
+		path           string
+		expected       bool
+		expectedReason Reason
+	}{
+		{"app.js", true, ForcedText},
+		{"app.min.js", false, ForcedBinary},
+		{"data.bin", true, ForcedText},
+		{"vendor/lib.js", false, ForcedBinary},
+		{"src/vendor.js", true, ForcedText},
+		{"assets/blob.data", false, ""},
+	}
+
+	for _, tt := range tests {
+		res := results[filepath.Join(root, tt.path)]
+		if res.IsPlaintext != tt.expected || res.Reason != tt.expectedReason {
+			t.Errorf("WalkDir() %s = %+v, want IsPlaintext %v and Reason %q", tt.path, res, tt.expected, tt.expectedReason)
+		}
+	}
+
+	if _, err := CheckFile(filepath.Join(root, "app.js"), WithForceText([]string{"["})); err == nil {
+		t.Errorf("CheckFile() with malformed pattern error = nil, want error")
+	}
+}
+
+func TestWithMaxOpenFiles(t *testing.T) {
+	files := make(map[string][]byte)
+	for i := range 200 {
+		files[fmt.Sprintf("dir%d/text%d.txt", i%8, i)] = []byte("line of text\n")
+		files[fmt.Sprintf("dir%d/binary%d.bin", i%8, i)] = []byte{0x00, 0x01, 0x02}
+	}
+	root := writeTree(t, files)
+	limit := WithMaxOpenFiles(2)
+
+	// Walk every subdirectory at once; all walks share the limit of the one option value.
+	results := make([]map[string]Result, 8)
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			results[i], errs[i] = WalkDir(filepath.Join(root, fmt.Sprintf("dir%d", i)), limit)
+		})
+	}
+	wg.Wait()
+
+	total := 0
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("WalkDir() error: %v", err)
+		}
+		for path, res := range results[i] {
+			if expected := strings.HasSuffix(path, ".txt"); res.IsPlaintext != expected {
+				t.Errorf("WalkDir() %s IsPlaintext = %v, want %v", path, res.IsPlaintext, expected)
+			}
+		}
+		total += len(results[i])
+	}
+	if total != len(files) {
+		t.Errorf("WalkDir() classified %d files, want %d", total, len(files))
+	}
+
+	// A check waits while every slot is taken.
+	o := buildOptions([]Option{WithMaxOpenFiles(1)})
+	o.openFiles <- struct{}{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := checkFile(filepath.Join(root, "dir0", "text0.txt"), o); err != nil {
+			t.Errorf("checkFile() error: %v", err)
+		}
+	}()
+	select {
+	case <-done:
+		t.Fatal("checkFile() opened a file beyond the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-o.openFiles
+	<-done
+	if len(o.openFiles) != 0 {
+		t.Errorf("open file slots in use = %d, want 0", len(o.openFiles))
+	}
+}
@@ -0,0 +1,44 @@
+package isplaintextfile
+
+import (
+	"archive/zip"
+	"fmt"
+)
+
+// EntryNotFoundError is returned when an archive has no entry with the requested name.
+type EntryNotFoundError struct {
+	// Name is the entry that was requested.
+	Name string
+}
+
+// Error implements the error interface.
+func (e *EntryNotFoundError) Error() string {
+	return fmt.Sprintf("archive entry not found: %s", e.Name)
+}
+
+// ZipEntry checks if the decompressed content of the entry with the given name in the zip archive
+// is plaintext. The name must match the entry's name in the archive exactly, such as
+// "docs/README.md". Use WithPreviewKB to check only the start of a large entry, and
+// WithMaxFileSize to skip entries whose uncompressed size is too large without decompressing
+// them. An *EntryNotFoundError is returned if the archive has no such entry.
+func ZipEntry(zr *zip.Reader, name string, opts ...Option) (bool, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+
+		o := buildOptions(opts)
+		if o.MaxFileSize > 0 && f.UncompressedSize64 > uint64(o.MaxFileSize) {
+			return false, nil
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return false, err
+		}
+		defer rc.Close()
+
+		res, err := check(rc, nil, o)
+		return res.IsPlaintext, err
+	}
+	return false, &EntryNotFoundError{Name: name}
+}
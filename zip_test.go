@@ -0,0 +1,64 @@
+package isplaintextfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestZipEntry(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string][]byte{
+		"docs/README.md": []byte("# Project\n\nA readme inside an archive.\n"),
+		"bin/app":        {0x7f, 'E', 'L', 'F', 0x02, 0x01, 0x01, 0x00, 0x00},
+		"logs/large.log": append(bytes.Repeat([]byte("log line\n"), 1000), 0x00),
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatalf("Failed to write zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip writer: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		entry    string
+		opts     []Option
+		expected bool
+	}{
+		{"text entry", "docs/README.md", nil, true},
+		{"binary entry", "bin/app", nil, false},
+		{"binary after the preview", "logs/large.log", []Option{WithPreviewKB(1)}, true},
+		{"binary without a preview", "logs/large.log", nil, false},
+		{"entry too large", "docs/README.md", []Option{WithMaxFileSize(10)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ZipEntry(zr, tt.entry, tt.opts...)
+			if err != nil {
+				t.Errorf("ZipEntry() error: %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("ZipEntry() = %v, want %v", res, tt.expected)
+			}
+		})
+	}
+
+	var notFound *EntryNotFoundError
+	if _, err := ZipEntry(zr, "README.md"); !errors.As(err, &notFound) || notFound.Name != "README.md" {
+		t.Errorf("ZipEntry() error = %v, want *EntryNotFoundError for README.md", err)
+	}
+}